@@ -21,6 +21,13 @@ type Logger interface {
 	// WithFunc copies the logger and adds a function as single field (using the key defined in KeyFunc) to the Logger.
 	WithFunc(function string) Logger
 
+	// AppendFunc copies the logger and appends a function to the field of WithFunc,
+	// joined by FuncSeparator, e.g. "handle -> query".
+	AppendFunc(function string) Logger
+
+	// AppendCaller copies the logger and appends the name of the calling function to the field of WithFunc.
+	AppendCaller() Logger
+
 	// WithCtx copies the logger and adds a context as single field (using the key defined in KeyContext) to the Logger.
 	WithCtx(ctx context.Context) Logger
 
@@ -36,6 +43,12 @@ type Logger interface {
 	// For this behavior Entry.Fatal should be used instead.
 	Logf(level Level, format string, args ...any)
 
+	// Trace will log a message at the trace level.
+	Trace(args ...any)
+
+	// Tracef will log a message at the trace level.
+	Tracef(format string, args ...any)
+
 	// Debug will log a message at the debug level.
 	Debug(args ...any)
 
@@ -48,6 +61,12 @@ type Logger interface {
 	// Infof will log a message at the info level.
 	Infof(format string, args ...any)
 
+	// Notice will log a message at the notice level.
+	Notice(args ...any)
+
+	// Noticef will log a message at the notice level.
+	Noticef(format string, args ...any)
+
 	// Warn will log a message at the warn level.
 	Warn(args ...any)
 
@@ -65,4 +84,24 @@ type Logger interface {
 
 	// Fatalf will log a message at the fatal level.
 	Fatalf(format string, args ...any)
+
+	// RecoverPanic recovers a panic and logs it at the error level with its stack.
+	// It must be called directly by defer:
+	//
+	//	defer logger.RecoverPanic()
+	RecoverPanic()
+
+	// TraceFunc appends the name of the calling function to the field of WithFunc, logs the entry
+	// at the trace level, and returns a function which logs the exit with the elapsed time (using
+	// the key defined in KeyElapsed):
+	//
+	//	defer logger.TraceFunc()()
+	TraceFunc() func()
+
+	// Start starts a timer of the named operation, whose Stop logs the elapsed time:
+	//
+	//	timer := logger.Start("query").Threshold(time.Second)
+	//	rows := query()
+	//	timer.Stop("rows", len(rows))
+	Start(name string) *Timer
 }
@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+// BufferedLogger is a logger which keeps its messages below LevelError in memory,
+// and writes them only when they are needed, e.g. when a request fails.
+type BufferedLogger interface {
+	Logger
+
+	// Flush writes the buffered messages through the parent logger and empties the buffer.
+	Flush() error
+
+	// Discard drops the buffered messages.
+	Discard()
+}
+
+type bufferedLogger struct {
+	buffer *internal.BufferHandler
+
+	*logger
+}
+
+// NewBufferedLogger creates a new buffered logger writing through the parent logger.
+//
+// The messages at or above the level and below LevelError are kept in memory, up to maxRecords
+// messages and maxBytes bytes of messages and fields, the oldest ones are dropped when they are
+// exceeded. Non-positive limits are unlimited. When a message at or above LevelError is logged,
+// the buffered messages enabled by the parent logger are written before it.
+//
+// The loggers derived by With and the other With methods are BufferedLogger sharing the buffer.
+// It's meant to be scoped to a request:
+//
+//	l := logs.NewBufferedLogger(logs.Default(), logs.LevelDebug, 1000, 1<<20)
+//	defer l.Discard()
+//	ctx = l.Attach(ctx)
+//
+// The messages are written by the handler of the parent logger, or by its methods when the parent
+// is not created by this package, in which case the groups are flattened into dotted keys.
+func NewBufferedLogger(parent Logger, level Level, maxRecords, maxBytes int) BufferedLogger {
+	h, ctx := parentHandler(parent)
+	buffer := internal.NewBufferHandler(h, int8(level), maxRecords, maxBytes)
+
+	return &bufferedLogger{
+		buffer: buffer,
+		logger: &logger{sl: slog.New(buffer), ctx: ctx},
+	}
+}
+
+// parentHandler returns the handler which writes the messages of the parent logger,
+// the ticker and sampling loggers are unwrapped, so their buffered messages are not dropped.
+func parentHandler(parent Logger) (slog.Handler, context.Context) {
+	switch l := parent.(type) {
+	case *logger:
+		return l.sl.Handler(), l.ctx
+	case *bufferedLogger:
+		return parentHandler(l.logger)
+	case *tickerLogger:
+		return parentHandler(l.Logger)
+	case *keyedTickerLogger:
+		return parentHandler(l.Logger)
+	case *sampledLogger:
+		return parentHandler(l.Logger)
+	}
+
+	return &logHandler{logger: parent}, bgCtx
+}
+
+// derive wraps the logger derived from the embedded logger, so it shares the buffer.
+func (l *bufferedLogger) derive(ll Logger) Logger {
+	return &bufferedLogger{buffer: l.buffer, logger: ll.(*logger)}
+}
+
+func (l *bufferedLogger) Copy() Logger {
+	return l.derive(l.logger.Copy())
+}
+
+func (l *bufferedLogger) With(args ...any) Logger {
+	return l.derive(l.logger.With(args...))
+}
+
+func (l *bufferedLogger) WithError(err error) Logger {
+	return l.derive(l.logger.WithError(err))
+}
+
+func (l *bufferedLogger) WithCtx(ctx context.Context) Logger {
+	return l.derive(l.logger.WithCtx(ctx))
+}
+
+func (l *bufferedLogger) WithFunc(function string) Logger {
+	return l.derive(l.logger.WithFunc(function))
+}
+
+func (l *bufferedLogger) AppendFunc(function string) Logger {
+	return l.derive(l.logger.AppendFunc(function))
+}
+
+func (l *bufferedLogger) AppendCaller() Logger {
+	return l.AppendFunc(callerFunc(1))
+}
+
+func (l *bufferedLogger) TraceFunc() func() {
+	return traceFunc(l.AppendFunc(callerFunc(1)))
+}
+
+func (l *bufferedLogger) Flush() error {
+	return l.buffer.Flush()
+}
+
+func (l *bufferedLogger) Discard() {
+	l.buffer.Discard()
+}
+
+func (l *bufferedLogger) Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logAttachKey, l)
+}
+
+func (l *bufferedLogger) RecoverPanic() {
+	if p := recover(); p != nil {
+		logPanic(l, p)
+	}
+}
+
+func (l *bufferedLogger) bindContext(ctx context.Context) Logger {
+	ll := *l.logger
+	ll.ctx = ctx
+	return &bufferedLogger{buffer: l.buffer, logger: &ll}
+}
+
+// logHandler adapts a Logger to slog.Handler, the records are written by the methods of the
+// logger, which filters them by itself. The groups become the prefixes of the keys.
+type logHandler struct {
+	logger Logger
+	prefix string
+}
+
+func (h *logHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *logHandler) Handle(_ context.Context, r slog.Record) error {
+	args := make([]any, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		args = append(args, h.attr(a))
+		return true
+	})
+
+	h.logger.With(args...).Log(Level(r.Level), r.Message)
+	return nil
+}
+
+func (h *logHandler) attr(a slog.Attr) slog.Attr {
+	a.Key = h.prefix + a.Key
+	return a
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	args := make([]any, 0, len(attrs))
+	for _, a := range attrs {
+		args = append(args, h.attr(a))
+	}
+
+	return &logHandler{logger: h.logger.With(args...), prefix: h.prefix}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	return &logHandler{logger: h.logger, prefix: h.prefix + name + "."}
+}
@@ -0,0 +1,180 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBufferedLogger(t *testing.T) {
+	writer := &bytes.Buffer{}
+	parent := New(LevelDebug, &Option{Output: writer})
+
+	l := NewBufferedLogger(parent, LevelDebug, 3, 0)
+	ctx := l.Attach(context.Background())
+
+	Get(ctx).Debug("debug 1")
+	Get(ctx).With("user", "alice").Debug("debug 2")
+	Get(ctx).Info("info 3")
+	Get(ctx).Info("info 4")
+	if writer.Len() != 0 {
+		t.Fatalf("expected messages to be buffered, got: %s", writer.String())
+	}
+
+	Get(ctx).Error("failed")
+	result := writer.String()
+	if strings.Contains(result, "debug 1") {
+		t.Errorf("expected the oldest message to be dropped, got: %s", result)
+	}
+
+	for _, msg := range []string{"debug 2", "alice", "info 3", "info 4", "failed"} {
+		if !strings.Contains(result, msg) {
+			t.Errorf("expected %q in output: %s", msg, result)
+		}
+	}
+
+	if strings.Index(result, "info 4") > strings.Index(result, "failed") {
+		t.Errorf("expected buffered messages before the error, got: %s", result)
+	}
+
+	writer.Reset()
+	l.Debug("discarded")
+	l.Discard()
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if writer.Len() != 0 {
+		t.Errorf("expected discarded messages to be dropped, got: %s", writer.String())
+	}
+
+	l.Debug("flushed")
+	if err := Get(ctx).(BufferedLogger).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(writer.String(), "flushed") {
+		t.Errorf("expected flushed message in output: %s", writer.String())
+	}
+}
+
+func TestBufferedLoggerDerived(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewBufferedLogger(New(LevelDebug, &Option{Output: writer}), LevelDebug, 0, 0)
+
+	derived := map[string]Logger{
+		"With":         l.With("user", "alice"),
+		"WithError":    l.WithError(errors.New("cause")),
+		"WithCtx":      l.WithCtx(context.Background()),
+		"WithFunc":     l.WithFunc("handle"),
+		"AppendFunc":   l.AppendFunc("handle"),
+		"AppendCaller": l.AppendCaller(),
+		"Copy":         l.Copy(),
+	}
+
+	for name, d := range derived {
+		ctx := d.Attach(context.Background())
+		buffered, ok := Get(ctx).(BufferedLogger)
+		if !ok {
+			t.Errorf("%s: expected a buffered logger, got %T", name, Get(ctx))
+			continue
+		}
+
+		buffered.Debug(name)
+		if writer.Len() != 0 {
+			t.Fatalf("%s: expected the message to be buffered, got: %s", name, writer.String())
+		}
+
+		if err := buffered.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(writer.String(), name) {
+			t.Errorf("%s: expected the shared buffer to be flushed: %s", name, writer.String())
+		}
+		writer.Reset()
+	}
+}
+
+func TestBufferedLoggerMaxBytes(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewBufferedLogger(New(LevelDebug, &Option{Output: writer}), LevelDebug, 0, 10)
+
+	l.Debug("12345")
+	l.Debug("abcde")
+	l.Debug("ABCDE")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := writer.String()
+	if strings.Contains(result, "12345") || !strings.Contains(result, "abcde") || !strings.Contains(result, "ABCDE") {
+		t.Errorf("expected only the latest 10 bytes of messages, got: %s", result)
+	}
+
+	writer.Reset()
+	l = NewBufferedLogger(New(LevelDebug, &Option{Output: writer}), LevelDebug, 0, 30)
+	derived := l.With("user", "alice")
+
+	derived.Debug("12345")
+	derived.Debug("abcde")
+	derived.Debug("ABCDE")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	result = writer.String()
+	if strings.Contains(result, "12345") || !strings.Contains(result, "abcde") || !strings.Contains(result, "ABCDE") {
+		t.Errorf("expected the fields added by With to be counted, got: %s", result)
+	}
+}
+
+type customLogger struct {
+	Logger
+}
+
+func TestBufferedLoggerParents(t *testing.T) {
+	parents := map[string]func(option *Option) Logger{
+		"ticker":   func(option *Option) Logger { return NewTickerLogger(LevelDebug, time.Hour, option) },
+		"sampling": func(option *Option) Logger { return NewSamplingLogger(LevelDebug, time.Hour, 1, 0, option) },
+		"buffered": func(option *Option) Logger {
+			return NewBufferedLogger(New(LevelDebug, option), LevelDebug, 0, 0)
+		},
+		"custom": func(option *Option) Logger { return customLogger{New(LevelDebug, option)} },
+	}
+
+	for name, newParent := range parents {
+		writer := &bytes.Buffer{}
+		parent := newParent(&Option{Output: writer, Format: FormatJSON})
+
+		l := NewBufferedLogger(parent, LevelDebug, 0, 0)
+		l.With("user", "alice").Debug("first")
+		l.Debug("second")
+		l.Error("failed")
+
+		if b, ok := parent.(BufferedLogger); ok {
+			if err := b.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result := writer.String()
+		for _, s := range []string{`"msg":"first"`, `"user":"alice"`, `"msg":"second"`, `"msg":"failed"`} {
+			if !strings.Contains(result, s) {
+				t.Errorf("%s: expected %s in output: %s", name, s, result)
+			}
+		}
+	}
+
+	writer := &bytes.Buffer{}
+	l := NewBufferedLogger(New(LevelInfo, &Option{Output: writer}), LevelDebug, 0, 0)
+	l.Debug("filtered")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if writer.Len() != 0 {
+		t.Errorf("expected the messages disabled by the parent to be dropped, got: %s", writer.String())
+	}
+}
@@ -0,0 +1,222 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/yanun0323/logs/internal"
+	"github.com/yanun0323/logs/internal/cbor"
+)
+
+// maxCBORFrameSize limits the size of a single record to protect the decoder from corrupted streams.
+const maxCBORFrameSize = 64 << 20
+
+// ErrInvalidCBORRecord is returned by CBORDecoder when a frame does not contain a valid record.
+var ErrInvalidCBORRecord = errors.New("logs: invalid cbor record")
+
+// CBORDecoder reads records written by a logger with FormatCBOR.
+type CBORDecoder struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewCBORDecoder creates a decoder reading the length-prefixed CBOR stream from r.
+func NewCBORDecoder(r io.Reader) *CBORDecoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &CBORDecoder{r: br}
+}
+
+// Decode reads the next record from the stream.
+//
+// It returns io.EOF when there are no more records.
+func (d *CBORDecoder) Decode() (slog.Record, error) {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return slog.Record{}, err
+	}
+
+	if size > maxCBORFrameSize {
+		return slog.Record{}, fmt.Errorf("%w: frame size %d exceeds limit", ErrInvalidCBORRecord, size)
+	}
+
+	if uint64(cap(d.buf)) < size {
+		d.buf = make([]byte, size)
+	}
+	d.buf = d.buf[:size]
+
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return slog.Record{}, err
+	}
+
+	item, _, err := cbor.Decode(d.buf)
+	if err != nil {
+		return slog.Record{}, fmt.Errorf("%w: %w", ErrInvalidCBORRecord, err)
+	}
+
+	m, ok := item.(cbor.Map)
+	if !ok {
+		return slog.Record{}, fmt.Errorf("%w: record is not a map", ErrInvalidCBORRecord)
+	}
+
+	var (
+		t     time.Time
+		level slog.Level
+		msg   string
+		attrs []slog.Attr
+	)
+
+	for _, e := range m {
+		key, _ := e.Key.(string)
+		switch key {
+		case internal.KeyCBORTime:
+			v, ok := decodeCBORValue(e.Value)
+			if !ok || v.Kind() != slog.KindTime {
+				return slog.Record{}, fmt.Errorf("%w: invalid time", ErrInvalidCBORRecord)
+			}
+			t = v.Time()
+		case internal.KeyCBORLevel:
+			n, ok := e.Value.(int64)
+			if !ok {
+				return slog.Record{}, fmt.Errorf("%w: invalid level", ErrInvalidCBORRecord)
+			}
+			level = slog.Level(n)
+		case internal.KeyCBORMsg:
+			msg, _ = e.Value.(string)
+		case internal.KeyCBORAttrs:
+			group, ok := e.Value.(cbor.Map)
+			if !ok {
+				return slog.Record{}, fmt.Errorf("%w: invalid attributes", ErrInvalidCBORRecord)
+			}
+			attrs = decodeCBORAttrs(group)
+		}
+	}
+
+	r := slog.NewRecord(t, level, msg, 0)
+	r.AddAttrs(attrs...)
+
+	return r, nil
+}
+
+func decodeCBORAttrs(m cbor.Map) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(m))
+	for _, e := range m {
+		key, ok := e.Key.(string)
+		if !ok {
+			key = fmt.Sprint(e.Key)
+		}
+
+		if v, ok := decodeCBORValue(e.Value); ok {
+			attrs = append(attrs, slog.Attr{Key: key, Value: v})
+		}
+	}
+
+	return attrs
+}
+
+func decodeCBORValue(item any) (slog.Value, bool) {
+	switch v := item.(type) {
+	case nil:
+		return slog.AnyValue(nil), true
+	case string:
+		return slog.StringValue(v), true
+	case int64:
+		return slog.Int64Value(v), true
+	case uint64:
+		return slog.Uint64Value(v), true
+	case float64:
+		return slog.Float64Value(v), true
+	case bool:
+		return slog.BoolValue(v), true
+	case []byte:
+		return slog.AnyValue(v), true
+	case []any:
+		return slog.AnyValue(decodeCBORAny(v)), true
+	case cbor.Map:
+		return slog.GroupValue(decodeCBORAttrs(v)...), true
+	case cbor.Tag:
+		if x, ok := decodeCBORTag(v); ok {
+			return slog.AnyValue(x), true
+		}
+		return decodeCBORValue(v.Content)
+	}
+
+	return slog.Value{}, false
+}
+
+// decodeCBORAny converts the elements of the arrays into Go values: maps into map[string]any,
+// arrays into []any, and the tagged times and durations into time.Time and time.Duration.
+func decodeCBORAny(item any) any {
+	switch v := item.(type) {
+	case []any:
+		arr := make([]any, len(v))
+		for i, e := range v {
+			arr[i] = decodeCBORAny(e)
+		}
+		return arr
+	case cbor.Map:
+		m := make(map[string]any, len(v))
+		for _, e := range v {
+			key, ok := e.Key.(string)
+			if !ok {
+				key = fmt.Sprint(e.Key)
+			}
+			m[key] = decodeCBORAny(e.Value)
+		}
+		return m
+	case cbor.Tag:
+		if x, ok := decodeCBORTag(v); ok {
+			return x
+		}
+		return decodeCBORAny(v.Content)
+	}
+
+	return item
+}
+
+// decodeCBORTag decodes the tagged times and durations.
+func decodeCBORTag(tag cbor.Tag) (any, bool) {
+	switch tag.Number {
+	case cbor.TagDateTime:
+		if s, ok := tag.Content.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t, true
+			}
+		}
+	case cbor.TagDuration:
+		m, ok := tag.Content.(cbor.Map)
+		if !ok {
+			return nil, false
+		}
+
+		var d time.Duration
+		for _, e := range m {
+			key, _ := e.Key.(int64)
+			n, ok := e.Value.(int64)
+			if !ok {
+				return nil, false
+			}
+
+			switch key {
+			case 1:
+				d += time.Duration(n) * time.Second
+			case -9:
+				d += time.Duration(n)
+			}
+		}
+		return d, true
+	}
+
+	return nil, false
+}
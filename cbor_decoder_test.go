@@ -0,0 +1,187 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelDebug, &Option{Format: FormatCBOR, Output: writer})
+
+	l.With("user_id", "i'm user", "count", 3, "ratio", 0.5, "ok", true).Info("first")
+	l.WithError(errors.New("boom")).Noticef("second %d", 2)
+
+	d := NewCBORDecoder(writer)
+
+	r, err := d.Decode()
+	if err != nil {
+		t.Fatalf("decode first record: %v", err)
+	}
+
+	if r.Message != "first" || r.Level != slog.LevelInfo {
+		t.Errorf("unexpected record: level %s, msg %q", r.Level, r.Message)
+	}
+
+	if time.Since(r.Time) > time.Minute {
+		t.Errorf("unexpected time: %s", r.Time)
+	}
+
+	want := map[string]any{"user_id": "i'm user", "count": int64(3), "ratio": 0.5, "ok": true}
+	r.Attrs(func(a slog.Attr) bool {
+		if v, ok := want[a.Key]; !ok || v != a.Value.Any() {
+			t.Errorf("unexpected attr %s=%v", a.Key, a.Value.Any())
+		}
+		delete(want, a.Key)
+		return true
+	})
+
+	if len(want) != 0 {
+		t.Errorf("missing attrs: %v", want)
+	}
+
+	r, err = d.Decode()
+	if err != nil {
+		t.Fatalf("decode second record: %v", err)
+	}
+
+	if r.Message != "second 2" || r.Level != slog.Level(LevelNotice) {
+		t.Errorf("unexpected record: level %s, msg %q", r.Level, r.Message)
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestCBORHandler(t *testing.T) {
+	writer := &bytes.Buffer{}
+	h := internal.NewCBORHandler(writer, int8(LevelDebug))
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		d := NewCBORDecoder(bytes.NewReader(writer.Bytes()))
+		for {
+			r, err := d.Decode()
+			if err == io.EOF {
+				return ms
+			}
+			if err != nil {
+				t.Fatalf("decode record: %v", err)
+			}
+
+			m := map[string]any{
+				slog.LevelKey:   r.Level,
+				slog.MessageKey: r.Message,
+			}
+			if !r.Time.IsZero() {
+				m[slog.TimeKey] = r.Time
+			}
+			r.Attrs(func(a slog.Attr) bool {
+				m[a.Key] = cborTestValue(a.Value)
+				return true
+			})
+
+			ms = append(ms, m)
+		}
+	}
+
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Error(err)
+	}
+
+	writer.Reset()
+	l := slog.New(h.WithGroup("G").WithAttrs([]slog.Attr{slog.Int("a", 1)}))
+	l.Info("merged", "b", 2)
+
+	r, err := NewCBORDecoder(writer).Decode()
+	if err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+
+	if r.NumAttrs() != 1 {
+		t.Errorf("expected a single group, but got %d attrs", r.NumAttrs())
+	}
+}
+
+func cborTestValue(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	m := map[string]any{}
+	for _, a := range v.Group() {
+		m[a.Key] = cborTestValue(a.Value)
+	}
+
+	return m
+}
+
+func TestCBORDecodeValues(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelDebug, &Option{Format: FormatCBOR, Output: writer})
+
+	l.With(
+		"elapsed", -1500*time.Millisecond,
+		"ids", []string{"a", "b"},
+		KeyErrorsStack, []Frame{{Function: "main.main", File: "main.go", Line: 3}},
+	).Info("values")
+
+	r, err := NewCBORDecoder(writer).Decode()
+	if err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "elapsed":
+			if a.Value.Kind() != slog.KindDuration || a.Value.Duration() != -1500*time.Millisecond {
+				t.Errorf("unexpected duration: %v", a.Value)
+			}
+		case "ids":
+			ids, ok := a.Value.Any().([]any)
+			if !ok || len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+				t.Errorf("unexpected array: %#v", a.Value.Any())
+			}
+		case KeyErrorsStack:
+			frames, ok := a.Value.Any().([]any)
+			if !ok || len(frames) != 1 {
+				t.Fatalf("unexpected stack: %#v", a.Value.Any())
+			}
+
+			frame, ok := frames[0].(map[string]any)
+			if !ok || frame["function"] != "main.main" || frame["line"] != int64(3) {
+				t.Errorf("unexpected frame: %#v", frames[0])
+			}
+		default:
+			t.Errorf("unexpected attr %s=%v", a.Key, a.Value)
+		}
+		return true
+	})
+}
+
+func TestCBORDecodeDepth(t *testing.T) {
+	// a record whose attribute nests arrays far deeper than the limit
+	item := bytes.Repeat([]byte{0x81}, 1<<16)
+	item = append(item, 0x00)
+
+	frame := []byte{0xa1, 0x65}
+	frame = append(frame, "attrs"...)
+	frame = append(frame, 0xa1, 0x61, 'a')
+	frame = append(frame, item...)
+
+	stream := binary.AppendUvarint(nil, uint64(len(frame)))
+	stream = append(stream, frame...)
+
+	if _, err := NewCBORDecoder(bytes.NewReader(stream)).Decode(); !errors.Is(err, ErrInvalidCBORRecord) {
+		t.Errorf("expected ErrInvalidCBORRecord, got %v", err)
+	}
+}
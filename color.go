@@ -0,0 +1,63 @@
+package logs
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode represents whether FormatConsole writes ANSI colors.
+type ColorMode int8
+
+const (
+	// ColorModeAuto writes colors only when the output is a terminal.
+	//
+	// NO_COLOR environment variable disables colors, FORCE_COLOR environment variable enables colors
+	// even if the output is not a terminal. This is the default mode.
+	ColorModeAuto ColorMode = iota
+
+	// ColorModeAlways always writes colors.
+	ColorModeAlways
+
+	// ColorModeNever never writes colors.
+	ColorModeNever
+)
+
+const (
+	_envNoColor    = "NO_COLOR"
+	_envForceColor = "FORCE_COLOR"
+)
+
+// enabled reports whether colors should be written to w.
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	}
+
+	if len(os.Getenv(_envNoColor)) != 0 {
+		return false
+	}
+
+	if force := os.Getenv(_envForceColor); len(force) != 0 && force != "0" && force != "false" {
+		return true
+	}
+
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device, e.g. a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
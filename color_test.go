@@ -0,0 +1,49 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestColorMode(t *testing.T) {
+	t.Setenv(_envNoColor, "")
+	t.Setenv(_envForceColor, "")
+
+	testCases := []struct {
+		desc      string
+		mode      ColorMode
+		env       string
+		wantColor bool
+	}{
+		{desc: "always", mode: ColorModeAlways, wantColor: true},
+		{desc: "never", mode: ColorModeNever, env: _envForceColor, wantColor: false},
+		{desc: "auto with buffer", mode: ColorModeAuto, wantColor: false},
+		{desc: "auto with FORCE_COLOR", mode: ColorModeAuto, env: _envForceColor, wantColor: true},
+		{desc: "auto with NO_COLOR", mode: ColorModeAuto, env: _envNoColor, wantColor: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if len(tc.env) != 0 {
+				t.Setenv(tc.env, "1")
+			}
+
+			writer := &bytes.Buffer{}
+			New(LevelInfo, &Option{Output: writer, Color: tc.mode}).
+				WithError(errString("failed")).
+				With("key", "value").
+				Info("message")
+
+			if hasColor := strings.Contains(writer.String(), "\x1b["); hasColor != tc.wantColor {
+				t.Errorf("expected color %t, got output %q", tc.wantColor, writer.String())
+			}
+		})
+	}
+}
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
@@ -11,4 +11,28 @@ const (
 
 	// KeyFunc is the key for the function field with highlight.
 	KeyFunc = internal.KeyFunc
+
+	// FuncSeparator separates the functions of the KeyFunc field appended by AppendFunc.
+	FuncSeparator = " -> "
+
+	// KeyElapsed is the key for the elapsed time of the function traced by TraceFunc and of Timer.
+	KeyElapsed = "elapsed"
+
+	// KeyErrorsStack is the key for the stack of the error, or the stack captured by Option.StackCapture.
+	KeyErrorsStack = internal.KeyErrorsStack
+
+	// KeySuppressed is the key for the number of messages dropped by a ticker logger.
+	KeySuppressed = "suppressed"
+
+	// KeySuppressedFirst is the key for the time of the first message dropped by a ticker logger.
+	KeySuppressedFirst = "suppressed.first"
+
+	// KeySuppressedLast is the key for the time of the last message dropped by a ticker logger.
+	KeySuppressedLast = "suppressed.last"
+
+	// KeyTruncated is the key for the field of the messages truncated by Option.Limits.
+	KeyTruncated = internal.KeyTruncated
+
+	// TruncationMarker is appended to the messages and values truncated by Option.Limits.
+	TruncationMarker = internal.TruncationMarker
 )
@@ -0,0 +1,23 @@
+package logs
+
+import (
+	"time"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+// NewDedupLogger creates a new logger which collapses consecutive identical messages.
+//
+// A message with the same level, message and fields as the previous one is not written,
+// instead, a single line "last message repeated N times" is written when a different message
+// arrives or the flush interval elapses. Zero interval flushes only on a different message.
+//
+// If option is not provided, the logger will write to the os.Stdout with console format.
+func NewDedupLogger(level Level, flushInterval time.Duration, option ...*Option) Logger {
+	opt := defaultOption
+	if len(option) != 0 {
+		opt = option[0]
+	}
+
+	return newLogger(internal.NewDedupHandler(opt.createLoggerHandler(level), flushInterval))
+}
@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDedupLogger(t *testing.T) {
+	writer := &syncBuffer{}
+	l := NewDedupLogger(LevelDebug, time.Hour, &Option{Output: writer})
+
+	for i := 0; i < 5; i++ {
+		l.With("id", 1).Warn("flapping")
+	}
+	l.With("id", 2).Warn("flapping")
+	l.Info("recovered")
+
+	result := writer.String()
+	if got := strings.Count(result, "\n"); got != 4 {
+		t.Errorf("Expected 4 lines, but got %d: %s", got, result)
+	}
+
+	if !strings.Contains(result, "last message repeated 4 times") {
+		t.Errorf("Expected summary line: %s", result)
+	}
+}
+
+func TestDedupLoggerFlushInterval(t *testing.T) {
+	writer := &syncBuffer{}
+	l := NewDedupLogger(LevelDebug, 20*time.Millisecond, &Option{Output: writer})
+
+	for i := 0; i < 3; i++ {
+		l.Info("flapping")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if result := writer.String(); !strings.Contains(result, "last message repeated 2 times") {
+		t.Errorf("Expected summary line after interval: %s", result)
+	}
+}
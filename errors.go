@@ -0,0 +1,18 @@
+package logs
+
+import "github.com/yanun0323/logs/internal"
+
+// Frame is a frame of the stack of an error.
+type Frame = internal.StackFrame
+
+// ErrorWithFields is implemented by the errors which contribute fields to the logs.
+//
+// The fields are written with the error in all formats, the fields of every layer of
+// an Unwrap() error chain are written.
+type ErrorWithFields = internal.ErrorWithFields
+
+// ErrorWithStack is implemented by the errors which contribute the frames of their stack to the logs.
+//
+// The stack of the innermost layer of an Unwrap() error chain is written, which is usually where the
+// error occurred. Errors with a StackTrace() method like github.com/pkg/errors are supported as well.
+type ErrorWithStack = internal.ErrorWithStack
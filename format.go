@@ -15,4 +15,28 @@ const (
 	// FormatJSON outputs logs in JSON format.
 	// Each log entry is a single JSON object on one line.
 	FormatJSON
+
+	// FormatCBOR outputs logs in CBOR (RFC 8949) binary format.
+	// Each log entry is a CBOR map prefixed with its length as an unsigned varint,
+	// use NewCBORDecoder to read the stream back.
+	FormatCBOR
 )
+
+// fieldOverhead is the estimated bytes written by the format around the key and the value
+// of each field, for Limits.MaxRecordBytes.
+func (f Format) fieldOverhead() int {
+	switch f {
+	case FormatText:
+		// key=value and the separator
+		return 2
+	case FormatJSON:
+		// "key":"value" and the comma
+		return 6
+	case FormatCBOR:
+		// the headers of the key and the value
+		return 2
+	default:
+		// [key] value and the separator
+		return 5
+	}
+}
@@ -0,0 +1,42 @@
+package logs
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// callerFunc returns the name of the function which is 'skip' frames above the caller of callerFunc,
+// without the package path, e.g. "handle", "(*Server).Serve" or "handle.func1".
+func callerFunc(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if _, after, ok := strings.Cut(name, "."); ok {
+		return after
+	}
+
+	return name
+}
+
+// traceFunc logs the entry at the trace level, and returns a function which logs the exit with the elapsed time.
+func traceFunc(l Logger) func() {
+	l.Trace("enter")
+	start := time.Now()
+
+	return func() {
+		l.With(KeyElapsed, time.Since(start)).Trace("exit")
+	}
+}
@@ -0,0 +1,94 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendFunc(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{Output: writer, Format: FormatJSON})
+
+	l.AppendFunc("handle").With("id", 1).AppendFunc("query").Info("chained")
+	result := writer.String()
+
+	if !strings.Contains(result, `"func":"handle -> query"`) {
+		t.Errorf("expected the chained functions: %s", result)
+	}
+
+	if strings.Count(result, `"func"`) != 1 {
+		t.Errorf("expected a single func field: %s", result)
+	}
+
+	if !strings.Contains(result, `"id":1`) {
+		t.Errorf("expected the fields added between the functions: %s", result)
+	}
+
+	writer.Reset()
+	l.WithFunc("handle").AppendFunc("query").WithFunc("replaced").Info("replaced")
+
+	if result := writer.String(); !strings.Contains(result, `"func":"replaced"`) || strings.Count(result, `"func"`) != 1 {
+		t.Errorf("expected the func field to be replaced: %s", result)
+	}
+
+	writer.Reset()
+	l.With("id", 1, KeyFunc, "handle").AppendFunc("query").Info("with")
+
+	if result := writer.String(); !strings.Contains(result, `"func":"handle -> query"`) || strings.Count(result, `"func"`) != 1 {
+		t.Errorf("expected the func field of With to be appended to: %s", result)
+	}
+
+	writer.Reset()
+	buffered := NewBufferedLogger(New(LevelDebug, &Option{Output: writer, Format: FormatJSON}), LevelDebug, 0, 0)
+	Get(buffered.Attach(context.Background())).AppendFunc("handle").AppendFunc("query").Info("buffered")
+	_ = buffered.Flush()
+
+	if result := writer.String(); !strings.Contains(result, `"func":"handle -> query"`) || strings.Count(result, `"func"`) != 1 {
+		t.Errorf("expected a single func field in the buffered logger: %s", result)
+	}
+}
+
+func TestAppendCaller(t *testing.T) {
+	for name, newLogger := range testLoggers(LevelTrace) {
+		writer := &bytes.Buffer{}
+		newLogger(&Option{Output: writer, Format: FormatJSON}).WithFunc("parent").AppendCaller().Error("caller")
+
+		if result := writer.String(); !strings.Contains(result, `"func":"parent -> TestAppendCaller"`) {
+			t.Errorf("%s: expected the caller to be appended: %s", name, result)
+		}
+	}
+}
+
+func tracedFunc(l Logger) {
+	defer l.TraceFunc()()
+	time.Sleep(time.Millisecond)
+}
+
+func TestTraceFunc(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelTrace, &Option{Output: writer, Format: FormatJSON})
+
+	tracedFunc(l.WithFunc("TestTraceFunc"))
+
+	lines := strings.Split(strings.TrimSpace(writer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected the entry and the exit: %s", writer.String())
+	}
+
+	for _, line := range lines {
+		if !strings.Contains(line, `"func":"TestTraceFunc -> tracedFunc"`) {
+			t.Errorf("expected the traced function: %s", line)
+		}
+	}
+
+	if !strings.Contains(lines[0], `"msg":"enter"`) {
+		t.Errorf("expected the entry: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], `"msg":"exit"`) || !strings.Contains(lines[1], `"`+KeyElapsed+`"`) {
+		t.Errorf("expected the exit with the elapsed time: %s", lines[1])
+	}
+}
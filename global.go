@@ -25,14 +25,30 @@ type logKey struct{}
 
 var logAttachKey = logKey{}
 
+// contextBinder is implemented by the loggers which can pass the context to the handler.
+type contextBinder interface {
+	bindContext(ctx context.Context) Logger
+}
+
 // Get gets the logger from context. if there's no logger in context, it will create a new logger with 'info' level.
+//
+// The context is passed to the handler of the returned logger, e.g. for Option.Sampling.
 func Get(ctx context.Context) Logger {
-	val := ctx.Value(logAttachKey)
-	if logger, ok := val.(Logger); ok {
-		return logger
+	logger, ok := ctx.Value(logAttachKey).(Logger)
+	if !ok {
+		logger = Default()
+	}
+
+	return bindContext(logger, ctx)
+}
+
+// bindContext passes the context to the handler of the logger when it's supported.
+func bindContext(logger Logger, ctx context.Context) Logger {
+	if b, ok := logger.(contextBinder); ok {
+		return b.bindContext(ctx)
 	}
 
-	return Default()
+	return logger
 }
 
 // Default returns the default logger.
@@ -100,6 +116,26 @@ func Infof(format string, args ...any) {
 	Default().Infof(format, args...)
 }
 
+// Notice uses the default logger to log a message at the notice level.
+func Notice(args ...any) {
+	Default().Notice(args...)
+}
+
+// Noticef uses the default logger to log a message at the notice level.
+func Noticef(format string, args ...any) {
+	Default().Noticef(format, args...)
+}
+
+// Trace uses the default logger to log a message at the trace level.
+func Trace(args ...any) {
+	Default().Trace(args...)
+}
+
+// Tracef uses the default logger to log a message at the trace level.
+func Tracef(format string, args ...any) {
+	Default().Tracef(format, args...)
+}
+
 // Warn uses the default logger to log a message at the warn level.
 func Warn(args ...any) {
 	Default().Warn(args...)
@@ -125,6 +161,26 @@ func WithFunc(function string) Logger {
 	return Default().WithFunc(function)
 }
 
+// AppendFunc appends a function to the field of WithFunc of the default logger.
+func AppendFunc(function string) Logger {
+	return Default().AppendFunc(function)
+}
+
+// AppendCaller appends the name of the calling function to the field of WithFunc of the default logger.
+func AppendCaller() Logger {
+	return Default().AppendFunc(callerFunc(1))
+}
+
+// TraceFunc uses the default logger to trace the calling function, see Logger.TraceFunc.
+func TraceFunc() func() {
+	return traceFunc(Default().AppendFunc(callerFunc(1)))
+}
+
+// Start starts a timer of the named operation with the default logger, see Logger.Start.
+func Start(name string) *Timer {
+	return newTimer(Default(), name)
+}
+
 // WithError attaches the logger to the context.
 func WithError(err error) Logger {
 	return Default().WithError(err)
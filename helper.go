@@ -3,8 +3,11 @@ package logs
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/yanun0323/logs/internal"
 )
 
 func getAbsPath(root string, dirs ...string) string {
@@ -22,6 +25,17 @@ func getAbsPath(root string, dirs ...string) string {
 	return path
 }
 
+// Lazy returns a field value computed by f only when the message is written,
+// so the expensive values cost nothing for the messages filtered by level.
+//
+// f is called once for each written message, the value is shared by the formats, sinks and hooks
+// of the message. The field added by With is computed again for each later message.
+//
+//	logger.With("dump", logs.Lazy(func() any { return dump(state) })).Debug("state")
+func Lazy(f func() any) slog.LogValuer {
+	return internal.Lazy(f)
+}
+
 // Json formats the given object to a JSON string.
 //
 // If the object is not JSON serializable, it returns a string with the object's value.
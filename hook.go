@@ -0,0 +1,167 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// _hookQueueSize is the number of messages waiting for the asynchronous hooks,
+// the hooks are fired synchronously when the queue is full.
+const _hookQueueSize = 1024
+
+// AllLevels are all the levels of the logger, e.g. for Hook.Levels.
+var AllLevels = []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal}
+
+// Hook is fired by the loggers created with Option.Hooks for the messages of its levels.
+//
+// Hooks are fired for the written messages, before they are written to the output.
+// An error returned or a panic raised by Fire is reported to os.Stderr, and never stops the logger.
+type Hook interface {
+	// Levels returns the levels the hook is fired for, it's called once when the logger is created.
+	Levels() []Level
+
+	// Fire is called with the message. The record is shared and must not be modified.
+	Fire(r *Record) error
+}
+
+// _hookWorkers counts the running workers of the asynchronous hooks, for FlushHooks.
+var _hookWorkers = struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	running int
+}{}
+
+func init() {
+	_hookWorkers.cond = sync.NewCond(&_hookWorkers.mu)
+}
+
+// FlushHooks waits until the asynchronous hooks of all the loggers fire their queued messages,
+// e.g. before the process exits.
+func FlushHooks() {
+	_hookWorkers.mu.Lock()
+	defer _hookWorkers.mu.Unlock()
+
+	for _hookWorkers.running != 0 {
+		_hookWorkers.cond.Wait()
+	}
+}
+
+// hookSink fires the hooks of the record level.
+type hookSink struct {
+	hooks map[Level][]Hook
+	async bool
+
+	// queue is consumed by a worker, which is running only while there are queued messages.
+	mu      sync.Mutex
+	idle    *sync.Cond
+	queue   []*Record
+	running bool
+}
+
+func newHookSink(hooks []Hook, async bool) *hookSink {
+	s := &hookSink{
+		hooks: make(map[Level][]Hook),
+		async: async,
+	}
+	s.idle = sync.NewCond(&s.mu)
+
+	for _, hook := range hooks {
+		for _, level := range hook.Levels() {
+			s.hooks[level] = append(s.hooks[level], hook)
+		}
+	}
+
+	return s
+}
+
+func (s *hookSink) enabled(level Level) bool {
+	return len(s.hooks[level]) != 0
+}
+
+// Write fires the hooks, the fatal messages are fired synchronously after the queued messages,
+// since the process exits right after they are written.
+func (s *hookSink) Write(r *Record) {
+	if !s.async {
+		s.fire(r)
+		return
+	}
+
+	if r.Level >= LevelFatal {
+		s.wait()
+		s.fire(r)
+		return
+	}
+
+	s.mu.Lock()
+	if len(s.queue) >= _hookQueueSize {
+		s.mu.Unlock()
+		s.fire(r)
+		return
+	}
+
+	s.queue = append(s.queue, r)
+	if !s.running {
+		s.running = true
+		_hookWorkers.mu.Lock()
+		_hookWorkers.running++
+		_hookWorkers.mu.Unlock()
+
+		go s.work()
+	}
+	s.mu.Unlock()
+}
+
+// work fires the queued messages, and stops when the queue is empty.
+func (s *hookSink) work() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.queue = nil
+			s.running = false
+			s.idle.Broadcast()
+			s.mu.Unlock()
+
+			_hookWorkers.mu.Lock()
+			_hookWorkers.running--
+			_hookWorkers.cond.Broadcast()
+			_hookWorkers.mu.Unlock()
+			return
+		}
+
+		r := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		s.fire(r)
+	}
+}
+
+// wait waits until the queued messages are fired.
+func (s *hookSink) wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.running {
+		s.idle.Wait()
+	}
+}
+
+func (s *hookSink) fire(r *Record) {
+	for _, hook := range s.hooks[r.Level] {
+		fireHook(hook, r)
+	}
+}
+
+func fireHook(hook Hook, r *Record) {
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Fprintf(os.Stderr, "logs: hook %T panicked: %v\n", hook, p)
+		}
+	}()
+
+	if err := hook.Fire(r); err != nil {
+		fmt.Fprintf(os.Stderr, "logs: failed to fire hook %T: %v\n", hook, err)
+	}
+}
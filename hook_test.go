@@ -0,0 +1,155 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testHook struct {
+	mu      sync.Mutex
+	levels  []Level
+	records []*Record
+	wg      *sync.WaitGroup
+	err     error
+	panic   bool
+}
+
+func (h *testHook) Levels() []Level {
+	return h.levels
+}
+
+func (h *testHook) Fire(r *Record) error {
+	if h.wg != nil {
+		defer h.wg.Done()
+	}
+
+	if h.panic {
+		panic("boom")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+
+	return h.err
+}
+
+func TestHook(t *testing.T) {
+	writer := &bytes.Buffer{}
+	errorHook := &testHook{levels: []Level{LevelError}}
+	allHook := &testHook{levels: AllLevels, err: errors.New("failed")}
+	panicHook := &testHook{levels: []Level{LevelWarn}, panic: true}
+
+	l := New(LevelInfo, &Option{
+		Output: writer,
+		Hooks:  []Hook{errorHook, allHook, panicHook},
+	})
+
+	l.Debug("filtered")
+	l.Info("info")
+	l.Warn("warn")
+	l.With("user", "alice").Error("error")
+
+	if len(errorHook.records) != 1 || errorHook.records[0].Message != "error" || errorHook.records[0].Attrs["user"] != "alice" {
+		t.Errorf("expected the error record, got %+v", errorHook.records)
+	}
+
+	if len(allHook.records) != 3 {
+		t.Errorf("expected 3 records, got %d", len(allHook.records))
+	}
+
+	result := writer.String()
+	for _, msg := range []string{"info", "warn", "error"} {
+		if !strings.Contains(result, msg) {
+			t.Errorf("expected %q in output: %s", msg, result)
+		}
+	}
+}
+
+func TestAsyncHook(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	hook := &testHook{levels: []Level{LevelInfo}, wg: wg}
+	l := New(LevelInfo, &Option{
+		Output:     &bytes.Buffer{},
+		Hooks:      []Hook{hook},
+		AsyncHooks: true,
+	})
+
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		l.Info("async")
+	}
+	wg.Wait()
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.records) != 10 {
+		t.Errorf("expected 10 records, got %d", len(hook.records))
+	}
+}
+
+func TestAsyncHookFatal(t *testing.T) {
+	hook := &testHook{levels: []Level{LevelInfo, LevelFatal}}
+	l := New(LevelInfo, &Option{
+		Output:     &bytes.Buffer{},
+		Hooks:      []Hook{hook},
+		AsyncHooks: true,
+	})
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 5000; i++ {
+		l.Info("async")
+	}
+
+	if n := runtime.NumGoroutine(); n > goroutines+1 {
+		t.Errorf("expected a single worker goroutine, got %d more", n-goroutines)
+	}
+
+	l.Log(LevelFatal, "fatal")
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.records) != 5001 {
+		t.Fatalf("expected the queued records to be fired before the fatal one, got %d", len(hook.records))
+	}
+
+	if hook.records[5000].Message != "fatal" {
+		t.Errorf("expected the fatal record at last, got %q", hook.records[5000].Message)
+	}
+}
+
+func TestFlushHooks(t *testing.T) {
+	hook := &testHook{levels: []Level{LevelInfo}}
+	goroutines := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		l := New(LevelInfo, &Option{
+			Output:     &bytes.Buffer{},
+			Hooks:      []Hook{hook},
+			AsyncHooks: true,
+		})
+		l.Info("async")
+	}
+
+	FlushHooks()
+
+	hook.mu.Lock()
+	n := len(hook.records)
+	hook.mu.Unlock()
+	if n != 10 {
+		t.Errorf("expected the queued records to be fired, got %d", n)
+	}
+
+	for i := 0; i < 100 && runtime.NumGoroutine() > goroutines; i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected the idle workers to stop, got %d more goroutines", n-goroutines)
+	}
+}
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// bufferState is shared by the handlers derived from the same buffer handler.
+type bufferState struct {
+	mu         sync.Mutex
+	maxRecords int
+	maxBytes   int
+	size       int
+	records    *list.List
+}
+
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	size    int
+}
+
+// BufferHandler is a handler which keeps the records below LevelError in memory.
+type BufferHandler struct {
+	next  slog.Handler
+	level slog.Level
+	state *bufferState
+
+	// attrsSize is the estimated size of the attributes and groups added by WithAttrs and WithGroup.
+	attrsSize int
+}
+
+// NewBufferHandler returns a handler which keeps the records at or above level and below
+// LevelError in memory, up to maxRecords records and maxBytes bytes of messages and attributes,
+// the oldest records are dropped when they are exceeded. Non-positive limits are unlimited.
+//
+// The buffered records enabled by next are handled by next when a record at or above LevelError
+// arrives, or when Flush is called.
+func NewBufferHandler(next slog.Handler, level int8, maxRecords, maxBytes int) *BufferHandler {
+	return &BufferHandler{
+		next:  next,
+		level: slog.Level(level),
+		state: &bufferState{
+			maxRecords: maxRecords,
+			maxBytes:   maxBytes,
+			records:    list.New(),
+		},
+	}
+}
+
+func (h *BufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= slog.Level(LevelError) {
+		return h.next.Enabled(ctx, level)
+	}
+
+	return level >= h.level
+}
+
+func (h *BufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Level < slog.Level(LevelError) {
+		s.push(&bufferedRecord{
+			ctx:     ctx,
+			handler: h.next,
+			record:  r.Clone(),
+			size:    h.attrsSize + recordSize(r),
+		})
+		return nil
+	}
+
+	err := s.flush()
+	if h.next.Enabled(ctx, r.Level) {
+		err = errors.Join(err, h.next.Handle(ctx, r))
+	}
+
+	return err
+}
+
+// Flush handles the buffered records and empties the buffer.
+func (h *BufferHandler) Flush() error {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	return h.state.flush()
+}
+
+// Discard drops the buffered records.
+func (h *BufferHandler) Discard() {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	h.state.records.Init()
+	h.state.size = 0
+}
+
+func (h *BufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	size := h.attrsSize
+	for _, a := range attrs {
+		size += estimateAttrSize(a)
+	}
+
+	return &BufferHandler{next: h.next.WithAttrs(attrs), level: h.level, state: h.state, attrsSize: size}
+}
+
+func (h *BufferHandler) WithGroup(name string) slog.Handler {
+	return &BufferHandler{next: h.next.WithGroup(name), level: h.level, state: h.state, attrsSize: h.attrsSize + len(name)}
+}
+
+func (s *bufferState) push(br *bufferedRecord) {
+	s.records.PushBack(br)
+	s.size += br.size
+
+	for s.records.Len() != 0 && (s.exceeds(s.records.Len(), s.maxRecords) || s.exceeds(s.size, s.maxBytes)) {
+		s.size -= s.records.Remove(s.records.Front()).(*bufferedRecord).size
+	}
+}
+
+func (s *bufferState) exceeds(n, limit int) bool {
+	return limit > 0 && n > limit
+}
+
+func (s *bufferState) flush() error {
+	var errs []error
+	for e := s.records.Front(); e != nil; e = e.Next() {
+		br := e.Value.(*bufferedRecord)
+		if !br.handler.Enabled(br.ctx, br.record.Level) {
+			continue
+		}
+
+		if err := br.handler.Handle(br.ctx, br.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.records.Init()
+	s.size = 0
+
+	return errors.Join(errs...)
+}
+
+// _anyValueSize is the estimated size of the values which are not formatted to be measured,
+// e.g. structs and slog.LogValuer.
+const _anyValueSize = 16
+
+// recordSize estimates the memory of the message and the attributes of a record.
+func recordSize(r slog.Record) int {
+	size := len(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		size += estimateAttrSize(a)
+		return true
+	})
+
+	return size
+}
+
+// estimateAttrSize estimates the memory of the attribute by the kind of its value, without formatting it.
+func estimateAttrSize(a slog.Attr) int {
+	size := len(a.Key)
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return size + len(a.Value.String())
+	case slog.KindBool:
+		return size + 1
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		return size + 8
+	case slog.KindTime:
+		return size + 24
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			size += estimateAttrSize(ga)
+		}
+		return size
+	}
+
+	if b, ok := a.Value.Any().([]byte); ok {
+		return size + len(b)
+	}
+
+	return size + _anyValueSize
+}
@@ -0,0 +1,303 @@
+// Package cbor provides the minimal subset of CBOR (RFC 8949) used to encode log records.
+package cbor
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// major types
+const (
+	MajorUint   byte = 0
+	MajorNegint byte = 1
+	MajorBytes  byte = 2
+	MajorString byte = 3
+	MajorArray  byte = 4
+	MajorMap    byte = 5
+	MajorTag    byte = 6
+	MajorSimple byte = 7
+)
+
+// TagDateTime is the standard tag for RFC 3339 date/time strings.
+const TagDateTime uint64 = 0
+
+// TagDuration is the tag for durations of RFC 9581, whose content is a map of
+// the seconds (key 1) and the nanoseconds (key -9).
+const TagDuration uint64 = 1002
+
+// MaxDepth is the maximum nesting depth of the arrays, maps and tags accepted by Decode.
+const MaxDepth = 64
+
+const (
+	_simpleFalse byte = 20
+	_simpleTrue  byte = 21
+	_simpleNull  byte = 22
+	_float16     byte = 25
+	_float32     byte = 26
+	_float64     byte = 27
+)
+
+var (
+	ErrUnexpectedEOF = errors.New("cbor: unexpected end of data")
+	ErrUnsupported   = errors.New("cbor: unsupported data item")
+	ErrTooDeep       = errors.New("cbor: nesting depth exceeds limit")
+)
+
+// Map is a decoded CBOR map that keeps the order of its entries.
+type Map []Entry
+
+// Entry is a single key/value pair of a Map.
+type Entry struct {
+	Key   any
+	Value any
+}
+
+// Tag is a decoded tagged data item.
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// AppendHead appends the initial byte and the argument of a data item.
+func AppendHead(b []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
+
+// AppendUint appends an unsigned integer.
+func AppendUint(b []byte, n uint64) []byte {
+	return AppendHead(b, MajorUint, n)
+}
+
+// AppendInt appends a signed integer.
+func AppendInt(b []byte, n int64) []byte {
+	if n < 0 {
+		return AppendHead(b, MajorNegint, uint64(-1-n))
+	}
+	return AppendHead(b, MajorUint, uint64(n))
+}
+
+// AppendFloat appends a double precision float.
+func AppendFloat(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, MajorSimple<<5|_float64), math.Float64bits(f))
+}
+
+// AppendBool appends a boolean.
+func AppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, MajorSimple<<5|_simpleTrue)
+	}
+	return append(b, MajorSimple<<5|_simpleFalse)
+}
+
+// AppendNull appends a null.
+func AppendNull(b []byte) []byte {
+	return append(b, MajorSimple<<5|_simpleNull)
+}
+
+// AppendString appends a text string.
+func AppendString(b []byte, s string) []byte {
+	return append(AppendHead(b, MajorString, uint64(len(s))), s...)
+}
+
+// AppendBytes appends a byte string.
+func AppendBytes(b []byte, p []byte) []byte {
+	return append(AppendHead(b, MajorBytes, uint64(len(p))), p...)
+}
+
+// AppendMapHeader appends the header of a map with n entries.
+func AppendMapHeader(b []byte, n int) []byte {
+	return AppendHead(b, MajorMap, uint64(n))
+}
+
+// AppendArrayHeader appends the header of an array with n elements.
+func AppendArrayHeader(b []byte, n int) []byte {
+	return AppendHead(b, MajorArray, uint64(n))
+}
+
+// AppendTag appends a tag number, the tagged content must be appended next.
+func AppendTag(b []byte, n uint64) []byte {
+	return AppendHead(b, MajorTag, n)
+}
+
+// Decode decodes one data item from b and returns it with the remaining bytes.
+//
+// Integers decode into int64 (or uint64 when they overflow int64), floats into float64,
+// text strings into string, byte strings into []byte, arrays into []any, maps into Map and
+// tags into Tag. Data items nested deeper than MaxDepth are rejected.
+func Decode(b []byte) (any, []byte, error) {
+	return decode(b, 0)
+}
+
+func decode(b []byte, depth int) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, b, ErrUnexpectedEOF
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+	if major == MajorSimple {
+		return decodeSimple(b, info)
+	}
+
+	n, b, err := decodeArgument(b, info)
+	if err != nil {
+		return nil, b, err
+	}
+
+	if major >= MajorArray && depth >= MaxDepth {
+		return nil, b, ErrTooDeep
+	}
+
+	switch major {
+	case MajorUint:
+		if n > math.MaxInt64 {
+			return n, b, nil
+		}
+		return int64(n), b, nil
+	case MajorNegint:
+		if n > math.MaxInt64 {
+			return nil, b, ErrUnsupported
+		}
+		return -1 - int64(n), b, nil
+	case MajorBytes, MajorString:
+		if uint64(len(b)) < n {
+			return nil, b, ErrUnexpectedEOF
+		}
+		if major == MajorString {
+			return string(b[:n]), b[n:], nil
+		}
+		p := make([]byte, n)
+		copy(p, b)
+		return p, b[n:], nil
+	case MajorArray:
+		if uint64(len(b)) < n {
+			return nil, b, ErrUnexpectedEOF
+		}
+		arr := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			var v any
+			if v, b, err = decode(b, depth+1); err != nil {
+				return nil, b, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, b, nil
+	case MajorMap:
+		if uint64(len(b)) < n {
+			return nil, b, ErrUnexpectedEOF
+		}
+		m := make(Map, 0, n)
+		for i := uint64(0); i < n; i++ {
+			var e Entry
+			if e.Key, b, err = decode(b, depth+1); err != nil {
+				return nil, b, err
+			}
+			if e.Value, b, err = decode(b, depth+1); err != nil {
+				return nil, b, err
+			}
+			m = append(m, e)
+		}
+		return m, b, nil
+	default: // MajorTag
+		var content any
+		if content, b, err = decode(b, depth+1); err != nil {
+			return nil, b, err
+		}
+		return Tag{Number: n, Content: content}, b, nil
+	}
+}
+
+func decodeArgument(b []byte, info byte) (uint64, []byte, error) {
+	b = b[1:]
+	switch {
+	case info < 24:
+		return uint64(info), b, nil
+	case info == 24:
+		if len(b) < 1 {
+			return 0, b, ErrUnexpectedEOF
+		}
+		return uint64(b[0]), b[1:], nil
+	case info == 25:
+		if len(b) < 2 {
+			return 0, b, ErrUnexpectedEOF
+		}
+		return uint64(binary.BigEndian.Uint16(b)), b[2:], nil
+	case info == 26:
+		if len(b) < 4 {
+			return 0, b, ErrUnexpectedEOF
+		}
+		return uint64(binary.BigEndian.Uint32(b)), b[4:], nil
+	case info == 27:
+		if len(b) < 8 {
+			return 0, b, ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint64(b), b[8:], nil
+	default:
+		// indefinite lengths are never produced by the encoder
+		return 0, b, ErrUnsupported
+	}
+}
+
+func decodeSimple(b []byte, info byte) (any, []byte, error) {
+	switch info {
+	case _simpleFalse:
+		return false, b[1:], nil
+	case _simpleTrue:
+		return true, b[1:], nil
+	case _simpleNull:
+		return nil, b[1:], nil
+	case _float16:
+		if len(b) < 3 {
+			return nil, b, ErrUnexpectedEOF
+		}
+		return float16(binary.BigEndian.Uint16(b[1:])), b[3:], nil
+	case _float32:
+		if len(b) < 5 {
+			return nil, b, ErrUnexpectedEOF
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b[1:]))), b[5:], nil
+	case _float64:
+		if len(b) < 9 {
+			return nil, b, ErrUnexpectedEOF
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b[1:])), b[9:], nil
+	default:
+		return nil, b, ErrUnsupported
+	}
+}
+
+func float16(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
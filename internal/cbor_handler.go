@@ -0,0 +1,265 @@
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/yanun0323/logs/internal/cbor"
+)
+
+const (
+	KeyCBORTime  = "time"
+	KeyCBORLevel = "level"
+	KeyCBORMsg   = "msg"
+	KeyCBORAttrs = "attrs"
+)
+
+var cborBufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+type cborHandler struct {
+	level *int8
+	// attrs[i] are the attributes added inside groups[:i], so attrs has one more element than groups.
+	attrs  [][]slog.Attr
+	groups []string
+	mu     *sync.Mutex
+	out    io.Writer
+}
+
+// NewCBORHandler returns a handler that writes each record as a CBOR map
+// prefixed with its length encoded as an unsigned varint.
+//
+// The map contains the keys "time" (tagged RFC 3339 string, omitted when zero), "level" (integer),
+// "msg" (string) and "attrs" (map of attributes, groups become nested maps). Durations are
+// tagged as RFC 9581 durations.
+func NewCBORHandler(w io.Writer, level int8) slog.Handler {
+	return &cborHandler{
+		level: &level,
+		mu:    &sync.Mutex{},
+		out:   w,
+		attrs: make([][]slog.Attr, 1),
+	}
+}
+
+func (h *cborHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.Level(*h.level)
+}
+
+func (h *cborHandler) Handle(ctx context.Context, r slog.Record) error {
+	bp := cborBufferPool.Get().(*[]byte)
+	defer cborBufferPool.Put(bp)
+
+	// reserve the largest varint so the frame can be written with a single call
+	b := append((*bp)[:0], make([]byte, binary.MaxVarintLen64)...)
+
+	attrs := h.recordAttrs(r)
+
+	fields := 2
+	if !r.Time.IsZero() {
+		fields++
+	}
+	if len(attrs) != 0 {
+		fields++
+	}
+
+	b = cbor.AppendMapHeader(b, fields)
+	if !r.Time.IsZero() {
+		b = cbor.AppendString(b, KeyCBORTime)
+		b = cbor.AppendTag(b, cbor.TagDateTime)
+		b = cbor.AppendString(b, r.Time.Format(time.RFC3339Nano))
+	}
+	b = cbor.AppendString(b, KeyCBORLevel)
+	b = cbor.AppendInt(b, int64(r.Level))
+	b = cbor.AppendString(b, KeyCBORMsg)
+	b = cbor.AppendString(b, r.Message)
+
+	if len(attrs) != 0 {
+		b = cbor.AppendString(b, KeyCBORAttrs)
+		b = cbor.AppendMapHeader(b, len(attrs))
+		for _, a := range attrs {
+			b = appendCBORAttr(b, a)
+		}
+	}
+
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(b)-binary.MaxVarintLen64))
+	start := binary.MaxVarintLen64 - n
+	copy(b[start:], prefix[:n])
+	*bp = b
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.out.Write(b[start:])
+	return err
+}
+
+// recordAttrs merges the attributes of the handler and of the record, so each open group
+// is written as a single map. The groups without attributes are omitted.
+func (h *cborHandler) recordAttrs(r slog.Record) []slog.Attr {
+	var attrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendCBORNormalized(attrs, a)
+		return true
+	})
+
+	for i := len(h.groups); i > 0; i-- {
+		if len(h.attrs[i]) != 0 {
+			attrs = append(h.attrs[i][:len(h.attrs[i]):len(h.attrs[i])], attrs...)
+		}
+
+		if len(attrs) != 0 {
+			attrs = []slog.Attr{{Key: h.groups[i-1], Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	if len(h.attrs[0]) == 0 {
+		return attrs
+	}
+
+	return append(h.attrs[0][:len(h.attrs[0]):len(h.attrs[0])], attrs...)
+}
+
+// appendCBORNormalized appends the attribute by the rules of slog.Handler: the values are resolved,
+// the empty attributes and groups are omitted, and the groups with empty keys are inlined.
+func appendCBORNormalized(attrs []slog.Attr, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, a)
+	}
+
+	var group []slog.Attr
+	for _, ga := range a.Value.Group() {
+		group = appendCBORNormalized(group, ga)
+	}
+
+	if len(group) == 0 {
+		return attrs
+	}
+
+	if len(a.Key) == 0 {
+		return append(attrs, group...)
+	}
+
+	return append(attrs, slog.Attr{Key: a.Key, Value: slog.GroupValue(group...)})
+}
+
+func appendCBORAttr(b []byte, a slog.Attr) []byte {
+	b = cbor.AppendString(b, a.Key)
+	return appendCBORValue(b, a.Value.Resolve())
+}
+
+func appendCBORValue(b []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return cbor.AppendString(b, v.String())
+	case slog.KindInt64:
+		return cbor.AppendInt(b, v.Int64())
+	case slog.KindUint64:
+		return cbor.AppendUint(b, v.Uint64())
+	case slog.KindFloat64:
+		return cbor.AppendFloat(b, v.Float64())
+	case slog.KindBool:
+		return cbor.AppendBool(b, v.Bool())
+	case slog.KindDuration:
+		d := v.Duration()
+		b = cbor.AppendTag(b, cbor.TagDuration)
+		b = cbor.AppendMapHeader(b, 2)
+		b = cbor.AppendInt(b, 1)
+		b = cbor.AppendInt(b, int64(d/time.Second))
+		b = cbor.AppendInt(b, -9)
+		return cbor.AppendInt(b, int64(d%time.Second))
+	case slog.KindTime:
+		b = cbor.AppendTag(b, cbor.TagDateTime)
+		return cbor.AppendString(b, v.Time().Format(time.RFC3339Nano))
+	case slog.KindGroup:
+		attrs := v.Group()
+		b = cbor.AppendMapHeader(b, len(attrs))
+		for _, a := range attrs {
+			b = appendCBORAttr(b, a)
+		}
+		return b
+	default:
+		switch x := v.Any().(type) {
+		case nil:
+			return cbor.AppendNull(b)
+		case []byte:
+			return cbor.AppendBytes(b, x)
+		case error:
+			return cbor.AppendString(b, x.Error())
+		case []string:
+			b = cbor.AppendArrayHeader(b, len(x))
+			for _, str := range x {
+				b = cbor.AppendString(b, str)
+			}
+			return b
+		case []StackFrame:
+			b = cbor.AppendArrayHeader(b, len(x))
+			for _, f := range x {
+				b = cbor.AppendMapHeader(b, 3)
+				b = cbor.AppendString(b, "function")
+				b = cbor.AppendString(b, f.Function)
+				b = cbor.AppendString(b, "file")
+				b = cbor.AppendString(b, f.File)
+				b = cbor.AppendString(b, "line")
+				b = cbor.AppendInt(b, int64(f.Line))
+			}
+			return b
+		default:
+			return cbor.AppendString(b, fmt.Sprintf("%+v", x))
+		}
+	}
+}
+
+func (h *cborHandler) clone() *cborHandler {
+	attrs := make([][]slog.Attr, len(h.attrs))
+	copy(attrs, h.attrs)
+	return &cborHandler{
+		level:  h.level,
+		attrs:  attrs,
+		groups: h.groups[:len(h.groups):len(h.groups)],
+		mu:     h.mu,
+		out:    h.out,
+	}
+}
+
+func (h *cborHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var normalized []slog.Attr
+	for _, a := range attrs {
+		normalized = appendCBORNormalized(normalized, a)
+	}
+
+	if len(normalized) == 0 {
+		return h
+	}
+
+	hh := h.clone()
+	last := len(hh.attrs) - 1
+	hh.attrs[last] = append(hh.attrs[last][:len(hh.attrs[last]):len(hh.attrs[last])], normalized...)
+
+	return hh
+}
+
+func (h *cborHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	hh := h.clone()
+	hh.groups = append(hh.groups, name)
+	hh.attrs = append(hh.attrs, nil)
+
+	return hh
+}
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	_fnvOffset64 uint64 = 14695981039346656037
+	_fnvPrime64  uint64 = 1099511628211
+)
+
+// dedupState is shared by the handlers derived from the same dedup handler.
+type dedupState struct {
+	mu       sync.Mutex
+	base     slog.Handler
+	interval time.Duration
+	timer    *time.Timer
+
+	hash    uint64
+	level   slog.Level
+	message string
+	count   int
+}
+
+type dedupHandler struct {
+	next  slog.Handler
+	hash  uint64
+	state *dedupState
+}
+
+// NewDedupHandler returns a handler which collapses the consecutive identical records.
+//
+// Records with the same level, message and attributes are counted instead of being handled,
+// a summary record "last message repeated N times" is handled by next when a different record
+// arrives or the interval elapses.
+func NewDedupHandler(next slog.Handler, interval time.Duration) slog.Handler {
+	return &dedupHandler{
+		next: next,
+		hash: _fnvOffset64,
+		state: &dedupState{
+			base:     next,
+			interval: interval,
+		},
+	}
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	hash := h.recordHash(r)
+
+	s := h.state
+	s.mu.Lock()
+
+	// the level and the message are compared too, so a collision of the hashes is unlikely to drop a record
+	if s.hash == hash && s.level == r.Level && s.message == r.Message {
+		s.count++
+		if s.timer == nil && s.interval > 0 {
+			s.timer = time.AfterFunc(s.interval, s.flush)
+		}
+		s.mu.Unlock()
+		return nil
+	}
+
+	summary, repeated := s.takeSummary()
+	s.hash = hash
+	s.level = r.Level
+	s.message = r.Message
+	s.mu.Unlock()
+
+	if repeated {
+		_ = s.base.Handle(ctx, summary)
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// recordHash hashes the level, the message and the attributes of the record.
+func (h *dedupHandler) recordHash(r slog.Record) uint64 {
+	hash := hashString(hashInt(h.hash, int64(r.Level)), r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		hash = hashAttr(hash, a)
+		return true
+	})
+
+	return hash
+}
+
+// flush handles the summary record of the repeated records.
+func (s *dedupState) flush() {
+	s.mu.Lock()
+	s.timer = nil
+	summary, repeated := s.takeSummary()
+	s.mu.Unlock()
+
+	if repeated {
+		_ = s.base.Handle(context.Background(), summary)
+	}
+}
+
+// takeSummary returns the summary record of the repeated records and resets the count,
+// it must be called with the lock held, and the record is handled after the lock is released.
+func (s *dedupState) takeSummary() (slog.Record, bool) {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.count == 0 {
+		return slog.Record{}, false
+	}
+
+	r := slog.NewRecord(time.Now(), s.level, "last message repeated "+strconv.Itoa(s.count)+" times", 0)
+	s.count = 0
+
+	return r, true
+}
+
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	hash := h.hash
+	for _, a := range attrs {
+		hash = hashAttr(hash, a)
+	}
+
+	return &dedupHandler{
+		next:  h.next.WithAttrs(attrs),
+		hash:  hash,
+		state: h.state,
+	}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	return &dedupHandler{
+		next:  h.next.WithGroup(name),
+		hash:  hashString(h.hash, name),
+		state: h.state,
+	}
+}
+
+// hashAttr mixes the key and the value of the attribute into the FNV-1a hash.
+func hashAttr(hash uint64, a slog.Attr) uint64 {
+	hash = hashString(hash, a.Key)
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return hashString(hash, v.String())
+	case slog.KindInt64:
+		return hashInt(hash, v.Int64())
+	case slog.KindUint64:
+		return hashInt(hash, int64(v.Uint64()))
+	case slog.KindFloat64, slog.KindBool, slog.KindDuration, slog.KindTime:
+		return hashString(hash, v.String())
+	case slog.KindGroup:
+		for _, ga := range v.Group() {
+			hash = hashAttr(hash, ga)
+		}
+		return hash
+	default:
+		return hashString(hash, ValueToString(v.Any()))
+	}
+}
+
+func hashString(hash uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		hash ^= uint64(s[i])
+		hash *= _fnvPrime64
+	}
+	// separator, so that ("ab", "c") and ("a", "bc") differ
+	hash ^= 0xff
+	hash *= _fnvPrime64
+	return hash
+}
+
+func hashInt(hash uint64, n int64) uint64 {
+	for i := 0; i < 8; i++ {
+		hash ^= uint64(byte(n >> (8 * i)))
+		hash *= _fnvPrime64
+	}
+	return hash
+}
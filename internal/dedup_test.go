@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler records the messages, and blocks the records of the message "slow" until release is closed.
+type blockingHandler struct {
+	mu       sync.Mutex
+	messages []string
+	blocked  chan struct{}
+	release  chan struct{}
+}
+
+func (h *blockingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *blockingHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.Message == "slow" {
+		close(h.blocked)
+		<-h.release
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+
+	return nil
+}
+
+func (h *blockingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *blockingHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestDedupHandlerConcurrentWrites(t *testing.T) {
+	next := &blockingHandler{blocked: make(chan struct{}), release: make(chan struct{})}
+	h := NewDedupHandler(next, 0)
+
+	go func() {
+		_ = h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "slow", 0))
+	}()
+	<-next.blocked
+
+	done := make(chan struct{})
+	go func() {
+		_ = h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "fast", 0))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected the record not to wait for the slow writer")
+	}
+
+	close(next.release)
+}
+
+func TestDedupHandlerHashCollision(t *testing.T) {
+	next := &blockingHandler{}
+	h := NewDedupHandler(next, 0).(*dedupHandler)
+
+	a := slog.NewRecord(time.Now(), slog.LevelInfo, "a", 0)
+	b := slog.NewRecord(time.Now(), slog.LevelInfo, "b", 0)
+
+	_ = h.Handle(context.Background(), a)
+
+	// simulate a collision of the hashes of the records
+	h.state.hash = h.recordHash(b)
+	_ = h.Handle(context.Background(), b)
+
+	if len(next.messages) != 2 || next.messages[1] != "b" {
+		t.Errorf("expected the different message to be handled, got %v", next.messages)
+	}
+}
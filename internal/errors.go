@@ -4,65 +4,398 @@ import (
 	"bytes"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/yanun0323/logs/internal/buffer"
 	"github.com/yanun0323/logs/internal/colorize"
 	"github.com/yanun0323/logs/internal/errors"
 )
 
-func extractErrors(err any) []slog.Attr {
+// keys of the error groups of the formats other than console
+const (
+	KeyErrorMessage = "message"
+	KeyErrorCause   = "cause"
+	KeyErrorChain   = "chain"
+	KeyErrorStack   = "stack"
+)
+
+// ErrorWithFields is implemented by the errors which contribute fields to the logs.
+type ErrorWithFields interface {
+	error
+	LogFields() []slog.Attr
+}
+
+// ErrorWithStack is implemented by the errors which contribute the frames of their stack to the logs.
+type ErrorWithStack interface {
+	error
+	LogStack() []StackFrame
+}
+
+// StackFrame is a frame of the stack of an error.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// errorInfo is the details of an error found by the supported conventions.
+type errorInfo struct {
+	// message is the message of the error, or of the outermost layer when there's a chain.
+	message string
+	// cause is the cause given by errors.Error.
+	cause error
+	// chain is the own message of each layer of the Unwrap() error chain, from the outermost.
+	chain []string
+	// fields are the fields of errors.Error and ErrorWithFields of all layers.
+	fields []slog.Attr
+	// frames are the frames of the innermost layer with a stack.
+	frames []StackFrame
+	// joined are the errors of Unwrap() []error.
+	joined []*errorInfo
+}
+
+// inspectError finds the details of err by the conventions of
+// github.com/yanun0323/errors, ErrorWithFields, ErrorWithStack, the StackTrace() method of
+// github.com/pkg/errors, the Unwrap() error chains and the Unwrap() []error trees,
+// at every layer of the chain.
+func inspectError(err error) *errorInfo {
 	if x, ok := err.(interface{ Unwrap() []error }); ok {
-		unwrapped := x.Unwrap()
-		result := make([]slog.Attr, 0, len(unwrapped))
-		for _, e := range unwrapped {
-			result = append(result, extractErrors(e)...)
+		return &errorInfo{message: err.Error(), joined: inspectJoined(x.Unwrap())}
+	}
+
+	info := &errorInfo{message: err.Error()}
+	if yanunErr, ok := err.(errors.Error); ok {
+		info.message = yanunErr.Message()
+	}
+
+	for layer := err; layer != nil; {
+		if x, ok := layer.(interface{ Unwrap() []error }); ok {
+			// the joined errors end the chain
+			info.joined = inspectJoined(x.Unwrap())
+			break
+		}
+
+		next := unwrapError(layer)
+
+		if next != nil || len(info.chain) != 0 {
+			info.chain = append(info.chain, ownMessage(layer, next))
+		}
+
+		if yanunErr, ok := layer.(errors.Error); ok {
+			if next == nil && info.cause == nil {
+				info.cause = yanunErr.Cause()
+			}
+
+			for _, a := range yanunErr.Attributes() {
+				if attr, ok := a.(errors.Attr); ok {
+					key, value := attr.Parameters()
+					info.fields = append(info.fields, slog.Any(key, value))
+				}
+			}
+		}
+
+		if x, ok := layer.(ErrorWithFields); ok {
+			info.fields = append(info.fields, x.LogFields()...)
+		}
+
+		if frames := layerFrames(layer); len(frames) != 0 {
+			info.frames = frames
+		}
+
+		layer = next
+	}
+
+	return info
+}
+
+func inspectJoined(errs []error) []*errorInfo {
+	joined := make([]*errorInfo, 0, len(errs))
+	for _, e := range errs {
+		if e != nil {
+			joined = append(joined, inspectError(e))
+		}
+	}
+
+	return joined
+}
+
+// unwrapError returns the next layer of the Unwrap() error chain, or nil.
+func unwrapError(err error) error {
+	if x, ok := err.(interface{ Unwrap() error }); ok {
+		return x.Unwrap()
+	}
+
+	return nil
+}
+
+// ownMessage returns the message of the layer without the message of the next layer,
+// e.g. "read config" of "read config: no such file".
+func ownMessage(layer, next error) string {
+	msg := layer.Error()
+	if next == nil {
+		return msg
+	}
+
+	if own, ok := strings.CutSuffix(msg, ": "+next.Error()); ok {
+		return own
+	}
+
+	return msg
+}
+
+// layerFrames returns the frames contributed by the layer itself.
+func layerFrames(err error) []StackFrame {
+	switch x := err.(type) {
+	case ErrorWithStack:
+		return x.LogStack()
+	case errors.Error:
+		return stackFrames(x)
+	}
+
+	return pkgStackFrames(err)
+}
+
+func stackFrames(err errors.Error) []StackFrame {
+	stack := err.Stack()
+	frames := make([]StackFrame, 0, len(stack))
+	for _, f := range stack {
+		frame, ok := f.(errors.Frame)
+		if !ok {
+			continue
 		}
-		return result
+
+		file, function, line := frame.Parameters()
+		n, _ := strconv.Atoi(line)
+		frames = append(frames, StackFrame{Function: function, File: file, Line: n})
 	}
 
-	yanunErr, ok := err.(errors.Error)
+	return frames
+}
+
+// _stackTracers caches the index of the StackTrace() method of each error type, or -1.
+var _stackTracers sync.Map
+
+// pkgStackFrames returns the frames of the StackTrace() method of github.com/pkg/errors,
+// which formats each frame as "function\n\tfile:line" with %+v.
+//
+// The method returns a type of github.com/pkg/errors, so it's found by reflection once per
+// error type, only for the errors implementing fmt.Formatter like the ones of github.com/pkg/errors.
+func pkgStackFrames(err error) []StackFrame {
+	if _, ok := err.(fmt.Formatter); !ok {
+		return nil
+	}
+
+	t := reflect.TypeOf(err)
+	index, ok := _stackTracers.Load(t)
 	if !ok {
+		i := -1
+		if m, ok := t.MethodByName("StackTrace"); ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			i = m.Index
+		}
+		index, _ = _stackTracers.LoadOrStore(t, i)
+	}
+
+	if index.(int) < 0 {
+		return nil
+	}
+
+	method := reflect.ValueOf(err).Method(index.(int))
+	text := fmt.Sprintf("%+v", method.Call(nil)[0].Interface())
+
+	var frames []StackFrame
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] != '\t' {
+			frames = append(frames, StackFrame{Function: line})
+			continue
+		}
+
+		if len(frames) == 0 {
+			continue
+		}
+
+		location := strings.TrimSpace(line)
+		if i := strings.LastIndexByte(location, ':'); i > 0 {
+			frames[len(frames)-1].File = location[:i]
+			frames[len(frames)-1].Line, _ = strconv.Atoi(location[i+1:])
+		} else {
+			frames[len(frames)-1].File = location
+		}
+	}
+
+	return frames
+}
+
+// hasDetails reports whether the error has more than its message.
+func (info *errorInfo) hasDetails() bool {
+	return info.cause != nil || len(info.chain) != 0 || len(info.fields) != 0 || len(info.frames) != 0 || len(info.joined) != 0
+}
+
+// extractErrors expands the error into the attributes of the console handler.
+//
+// The attributes of the stacks have the keys ending with ".stack".
+func extractErrors(err any, stackColor colorize.Color) []slog.Attr {
+	e, ok := err.(error)
+	if !ok || e == nil {
 		return []slog.Attr{
 			slog.String(KeyErr, fmt.Sprintf("%+v", err)),
 		}
 	}
 
-	args := make([]slog.Attr, 0, len(yanunErr.Attributes())+len(yanunErr.Stack())+2)
-	args = append(args, slog.String(KeyErr, yanunErr.Message()))
-	args = append(args, slog.Any(KeyErrorsCause, yanunErr.Cause()))
+	return inspectError(e).consoleAttrs(KeyErr, stackColor, nil)
+}
 
-	for _, a := range yanunErr.Attributes() {
-		attr, ok := a.(errors.Attr)
-		if !ok {
-			continue
+func (info *errorInfo) consoleAttrs(key string, stackColor colorize.Color, attrs []slog.Attr) []slog.Attr {
+	if len(info.joined) != 0 && info.cause == nil && len(info.chain) == 0 && len(info.fields) == 0 && len(info.frames) == 0 {
+		// errors.Join only
+		return info.joinedConsoleAttrs(key, stackColor, attrs)
+	}
+
+	attrs = append(attrs, slog.String(key, info.message))
+
+	if info.cause != nil {
+		attrs = append(attrs, slog.Any(key+"."+KeyErrorCause, info.cause))
+	}
+
+	if len(info.chain) != 0 {
+		attrs = append(attrs, slog.Any(key+"."+KeyErrorChain, info.chain))
+	}
+
+	for _, a := range info.fields {
+		if key != KeyErr {
+			a.Key = key + "." + a.Key
 		}
+		attrs = append(attrs, slog.String(a.Key, fmt.Sprintf("%+v", a.Value.Resolve().Any())))
+	}
 
-		key, value := attr.Parameters()
-		args = append(args, slog.String(key, fmt.Sprintf("%+v", value)))
+	if len(info.frames) != 0 {
+		attrs = append(attrs, slog.String(key+"."+KeyErrorStack, consoleStack(info.frames, stackColor)))
 	}
 
+	return info.joinedConsoleAttrs(key, stackColor, attrs)
+}
+
+func (info *errorInfo) joinedConsoleAttrs(key string, stackColor colorize.Color, attrs []slog.Attr) []slog.Attr {
+	for i, joined := range info.joined {
+		attrs = joined.consoleAttrs(key+"."+strconv.Itoa(i), stackColor, attrs)
+	}
+
+	return attrs
+}
+
+func consoleStack(frames []StackFrame, stackColor colorize.Color) string {
 	buf := buffer.Pool.Get().(*bytes.Buffer)
 	defer buffer.Put(buf)
 	buf.Reset()
 	buf.Grow(1024)
 
-	for _, f := range yanunErr.Stack() {
-		frame, ok := f.(errors.Frame)
-		if !ok {
-			continue
-		}
-
-		file, function, line := frame.Parameters()
+	for _, frame := range frames {
 		buf.WriteString("        ")
-		colorize.Fprint(buf, colorize.ColorBrightBlue, "[", function, "]")
+		if len(stackColor) != 0 {
+			colorize.Fprint(buf, stackColor, "[", frame.Function, "]")
+		} else {
+			buf.WriteString("[")
+			buf.WriteString(frame.Function)
+			buf.WriteString("]")
+		}
 		buf.WriteByte(' ')
-		buf.WriteString(file)
+		buf.WriteString(frame.File)
 		buf.WriteByte(':')
-		buf.WriteString(line)
+		buf.WriteString(strconv.Itoa(frame.Line))
 		buf.WriteByte('\n')
 	}
 
-	args = append(args, slog.String(KeyErrorsStack, buf.String()))
+	return buf.String()
+}
+
+// ErrorReplacer returns a function which expands the attributes of the errors with details into groups
+// of "message", "cause", "chain", the fields of the error and "stack", for the formats other than console.
+// The errors of errors.Join become the groups "0", "1", ... of the group.
+//
+// The stack is a []StackFrame, or a string of a frame per line when textual is true,
+// so is the chain a []string or a string of a layer per line. The []StackFrame values
+// of other attributes, e.g. the captured stacks, become strings as well when textual is true.
+func ErrorReplacer(textual bool) func(a slog.Attr) slog.Attr {
+	return func(a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindAny {
+			return a
+		}
+
+		if frames, ok := a.Value.Any().([]StackFrame); ok && textual {
+			a.Value = slog.StringValue(stackText(frames))
+			return a
+		}
+
+		err, ok := a.Value.Any().(error)
+		if !ok || err == nil {
+			return a
+		}
+
+		info := inspectError(err)
+		if !info.hasDetails() {
+			return a
+		}
+
+		a.Value = info.group(textual)
+		return a
+	}
+}
+
+func (info *errorInfo) group(textual bool) slog.Value {
+	attrs := make([]slog.Attr, 0, len(info.fields)+len(info.joined)+4)
+	attrs = append(attrs, slog.String(KeyErrorMessage, info.message))
+
+	if info.cause != nil {
+		attrs = append(attrs, slog.String(KeyErrorCause, info.cause.Error()))
+	}
+
+	if len(info.chain) != 0 {
+		if textual {
+			attrs = append(attrs, slog.String(KeyErrorChain, strings.Join(info.chain, "\n")))
+		} else {
+			attrs = append(attrs, slog.Any(KeyErrorChain, info.chain))
+		}
+	}
+
+	attrs = append(attrs, info.fields...)
+
+	if len(info.frames) != 0 {
+		if textual {
+			attrs = append(attrs, slog.String(KeyErrorStack, stackText(info.frames)))
+		} else {
+			attrs = append(attrs, slog.Any(KeyErrorStack, info.frames))
+		}
+	}
+
+	for i, joined := range info.joined {
+		attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: joined.group(textual)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// stackText writes a frame per line as "function file:line".
+func stackText(frames []StackFrame) string {
+	buf := buffer.Pool.Get().(*bytes.Buffer)
+	defer buffer.Put(buf)
+	buf.Reset()
+
+	for i, frame := range frames {
+		if i != 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(frame.Function)
+		buf.WriteByte(' ')
+		buf.WriteString(frame.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(frame.Line))
+	}
 
-	return args
+	return buf.String()
 }
@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type testFrame struct {
+	file, function, line string
+}
+
+func (f testFrame) Parameters() (string, string, string) {
+	return f.file, f.function, f.line
+}
+
+type testAttr struct {
+	key   string
+	value any
+}
+
+func (a testAttr) Parameters() (string, any) {
+	return a.key, a.value
+}
+
+type testError struct{}
+
+func (testError) Error() string { return "query failed: connection refused" }
+
+func (testError) Message() string { return "query failed" }
+
+func (testError) Cause() error { return errors.New("connection refused") }
+
+func (testError) Stack() []any {
+	return []any{
+		testFrame{file: "/app/db.go", function: "app.query", line: "42"},
+		testFrame{file: "/app/main.go", function: "main.main", line: "7"},
+	}
+}
+
+func (testError) Attributes() []any {
+	return []any{testAttr{key: "table", value: "users"}}
+}
+
+func TestErrorReplacerJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	replace := ErrorReplacer(false)
+	l := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr { return replace(a) },
+	}))
+
+	l.Error("failed", KeyErr, testError{})
+
+	var record struct {
+		Error struct {
+			Message string       `json:"message"`
+			Cause   string       `json:"cause"`
+			Table   string       `json:"table"`
+			Stack   []StackFrame `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+
+	e := record.Error
+	if e.Message != "query failed" || e.Cause != "connection refused" || e.Table != "users" {
+		t.Errorf("unexpected error fields: %s", buf.String())
+	}
+
+	if len(e.Stack) != 2 || e.Stack[0] != (StackFrame{Function: "app.query", File: "/app/db.go", Line: 42}) {
+		t.Errorf("unexpected stack: %s", buf.String())
+	}
+
+	if strings.Contains(buf.String(), _colorPrefix) {
+		t.Errorf("expected no color codes: %s", buf.String())
+	}
+}
+
+func TestErrorReplacerText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	replace := ErrorReplacer(true)
+	l := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr { return replace(a) },
+	}))
+
+	l.Error("failed", KeyErr, testError{})
+
+	result := buf.String()
+	if strings.Count(result, "\n") != 1 {
+		t.Errorf("expected a single line: %s", result)
+	}
+
+	for _, s := range []string{
+		`error.message="query failed"`,
+		`error.cause="connection refused"`,
+		`error.stack="app.query /app/db.go:42\nmain.main /app/main.go:7"`,
+	} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected %s in output: %s", s, result)
+		}
+	}
+}
+
+func TestExtractErrorsNoColor(t *testing.T) {
+	for _, a := range extractErrors(testError{}, "") {
+		if strings.Contains(a.Value.String(), _colorPrefix) {
+			t.Errorf("expected no color codes in %s: %s", a.Key, a.Value.String())
+		}
+	}
+}
+
+type pkgStackTrace []string
+
+func (st pkgStackTrace) Format(s fmt.State, verb rune) {
+	for _, f := range st {
+		fmt.Fprintf(s, "\n%s\n\t/app/%s.go:%d", f, f, len(f))
+	}
+}
+
+type pkgError struct {
+	msg string
+}
+
+func (e pkgError) Error() string { return e.msg }
+
+func (e pkgError) StackTrace() pkgStackTrace { return pkgStackTrace{"app.load", "main.main"} }
+
+// Format makes pkgError a fmt.Formatter like the errors of github.com/pkg/errors.
+func (e pkgError) Format(s fmt.State, verb rune) { fmt.Fprint(s, e.msg) }
+
+type fieldsError struct {
+	err error
+}
+
+func (e fieldsError) Error() string { return "handle request: " + e.err.Error() }
+
+func (e fieldsError) Unwrap() error { return e.err }
+
+func (e fieldsError) LogFields() []slog.Attr { return []slog.Attr{slog.Int("status", 500)} }
+
+func (e fieldsError) LogStack() []StackFrame {
+	return []StackFrame{{Function: "app.handle", File: "/app/handle.go", Line: 10}}
+}
+
+func attrsMap(attrs []slog.Attr) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value.String()
+	}
+	return m
+}
+
+func TestInspectErrorChain(t *testing.T) {
+	err := fmt.Errorf("read config: %w", fmt.Errorf("open file: %w", pkgError{msg: "no such file"}))
+
+	info := inspectError(err)
+	if strings.Join(info.chain, "|") != "read config|open file|no such file" {
+		t.Errorf("unexpected chain: %q", info.chain)
+	}
+
+	if len(info.frames) != 2 || info.frames[0] != (StackFrame{Function: "app.load", File: "/app/app.load.go", Line: 8}) {
+		t.Errorf("unexpected frames: %+v", info.frames)
+	}
+
+	attrs := attrsMap(extractErrors(err, ""))
+	if attrs[KeyErr] != err.Error() || !strings.Contains(attrs[KeyErrorsStack], "[app.load] /app/app.load.go:8") {
+		t.Errorf("unexpected console attrs: %+v", attrs)
+	}
+}
+
+func TestInspectErrorCustom(t *testing.T) {
+	err := fieldsError{err: pkgError{msg: "timeout"}}
+
+	attrs := attrsMap(extractErrors(err, ""))
+	if attrs["status"] != "500" {
+		t.Errorf("expected the contributed field: %+v", attrs)
+	}
+
+	// the innermost stack wins
+	if !strings.Contains(attrs[KeyErrorsStack], "app.load") || strings.Contains(attrs[KeyErrorsStack], "app.handle") {
+		t.Errorf("expected the innermost stack: %s", attrs[KeyErrorsStack])
+	}
+
+	attrs = attrsMap(extractErrors(fieldsError{err: errors.New("timeout")}, ""))
+	if !strings.Contains(attrs[KeyErrorsStack], "app.handle") {
+		t.Errorf("expected the contributed stack: %s", attrs[KeyErrorsStack])
+	}
+}
+
+func TestInspectErrorJoin(t *testing.T) {
+	err := errors.Join(errors.New("first"), fmt.Errorf("second: %w", errors.New("inner")))
+
+	attrs := attrsMap(extractErrors(err, ""))
+	if attrs[KeyErr+".0"] != "first" || attrs[KeyErr+".1"] != "second: inner" || len(attrs[KeyErr+".1.chain"]) == 0 {
+		t.Errorf("expected index keyed errors: %+v", attrs)
+	}
+
+	buf := &bytes.Buffer{}
+	replace := ErrorReplacer(false)
+	slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr { return replace(a) },
+	})).Error("failed", KeyErr, err)
+
+	var record struct {
+		Error map[string]any `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := record.Error["1"].(map[string]any)
+	if record.Error["0"] == nil || second["chain"] == nil {
+		t.Errorf("expected index keyed groups: %s", buf.String())
+	}
+}
+
+func TestInspectErrorNested(t *testing.T) {
+	err := fmt.Errorf("wrap: %w", errors.Join(errors.New("a"), errors.New("b")))
+
+	info := inspectError(err)
+	if strings.Join(info.chain, "|") != "wrap" || len(info.joined) != 2 || info.joined[1].message != "b" {
+		t.Errorf("expected the joined errors to be expanded: chain %q, joined %d", info.chain, len(info.joined))
+	}
+
+	attrs := attrsMap(extractErrors(err, ""))
+	if attrs[KeyErr] != err.Error() || attrs[KeyErr+".0"] != "a" || attrs[KeyErr+".1"] != "b" {
+		t.Errorf("expected index keyed errors after the chain: %+v", attrs)
+	}
+
+	err = fmt.Errorf("handle: %w", testError{})
+
+	info = inspectError(err)
+	if info.cause == nil || info.cause.Error() != "connection refused" {
+		t.Errorf("expected the cause of the wrapped error: %v", info.cause)
+	}
+
+	if attrs := attrsMap(info.fields); attrs["table"] != "users" {
+		t.Errorf("expected the fields of the wrapped error: %+v", attrs)
+	}
+
+	if len(info.frames) != 2 || info.frames[0].Function != "app.query" {
+		t.Errorf("expected the frames of the wrapped error: %+v", info.frames)
+	}
+}
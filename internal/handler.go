@@ -3,10 +3,11 @@ package internal
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"log/slog"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/yanun0323/logs/internal/buffer"
 	"github.com/yanun0323/logs/internal/colorize"
@@ -30,33 +31,59 @@ const (
 // 預計算的 level 字串，避免運行時計算
 var (
 	levelTitleCache = map[int8]string{
-		LevelDebug: LevelDebugTitle,
-		LevelInfo:  LevelInfoTitle,
-		LevelWarn:  LevelWarnTitle,
-		LevelError: LevelErrorTitle,
-		LevelFatal: LevelFatalTitle,
-	}
-
-	levelColorCache = map[int8]colorize.Color{
-		LevelDebug: colorize.ColorBlue,
-		LevelInfo:  colorize.ColorGreen,
-		LevelWarn:  colorize.ColorYellow,
-		LevelError: colorize.ColorRed,
-		LevelFatal: colorize.ColorRedReversed,
+		LevelTrace:  LevelTraceTitle,
+		LevelDebug:  LevelDebugTitle,
+		LevelInfo:   LevelInfoTitle,
+		LevelNotice: LevelNoticeTitle,
+		LevelWarn:   LevelWarnTitle,
+		LevelError:  LevelErrorTitle,
+		LevelFatal:  LevelFatalTitle,
 	}
 )
 
+// ConsoleOption is the configuration of the console handler.
+type ConsoleOption struct {
+	// Name is the logger name rendered by the {logger} placeholder.
+	Name string
+	// Layout is the layout of each line, DefaultLayout is used when nil.
+	Layout *Layout
+	// AttrStyle is the style of the attributes.
+	AttrStyle AttrStyle
+	// NoColor disables the ANSI colors.
+	NoColor bool
+	// Theme is the palette of the colors, DefaultTheme is used when nil.
+	Theme *Theme
+
+	keyCache map[string]string
+}
+
 type loggerHandler struct {
 	level *int8
 	attrs []slog.Attr
 	out   io.Writer
+	opt   *ConsoleOption
 }
 
-func NewLoggerHandler(w io.Writer, level int8) slog.Handler {
+func NewLoggerHandler(w io.Writer, level int8, opt ...*ConsoleOption) slog.Handler {
+	o := &ConsoleOption{}
+	if len(opt) != 0 && opt[0] != nil {
+		*o = *opt[0]
+	}
+
+	if o.Layout == nil {
+		o.Layout = defaultLayout
+	}
+
+	if o.Theme == nil {
+		o.Theme = DefaultTheme
+	}
+	o.keyCache = o.Theme.keyCache()
+
 	return &loggerHandler{
 		level: &level,
 		out:   w,
 		attrs: make([]slog.Attr, 0),
+		opt:   o,
 	}
 }
 
@@ -75,19 +102,46 @@ func (h *loggerHandler) Handle(ctx context.Context, r slog.Record) error {
 	buf.Reset()
 	buf.Grow(256)
 
-	timeStr := r.Time.Format(GetDefaultTimeFormat())
-	colorize.Fprint(buf, colorize.ColorBlack, timeStr)
-	buf.WriteByte(_space)
-
-	level := int8(r.Level)
-	colorize.Fprint(buf, LevelColor(level), LevelTitle(level))
-	buf.WriteByte(_space)
+	var (
+		stacks    []slog.Attr
+		attrsDone bool
+	)
+	for _, seg := range h.opt.Layout.segments {
+		switch seg.field {
+		case layoutLiteral:
+			buf.WriteString(seg.literal)
+		case layoutTime:
+			timeStr := r.Time.Format(GetDefaultTimeFormat())
+			h.writeAligned(buf, seg, h.opt.Theme.Time, timeStr)
+		case layoutLevel:
+			level := int8(r.Level)
+			title := LevelTitle(level)
+			if seg.width != 0 {
+				title = strings.TrimRight(title, " ")
+			}
+			h.writeAligned(buf, seg, h.opt.Theme.LevelColor(level), title)
+		case layoutLogger:
+			h.writeAligned(buf, seg, "", h.opt.Name)
+		case layoutMsg:
+			h.writeAligned(buf, seg, h.opt.Theme.Message, r.Message)
+		case layoutAttrs:
+			stacks = h.writeAttrs(buf, r)
+			attrsDone = true
+		}
+	}
 
-	buf.WriteString(r.Message)
-	buf.WriteByte(_space)
-	buf.WriteByte(_space)
+	if !attrsDone {
+		// the stacks are written even if the layout has no attributes
+		stacks = h.writeAttrs(nil, r)
+	}
 
-	h.writeAttrs(buf)
+	for _, stack := range stacks {
+		buf.WriteByte('\n')
+		buf.WriteString("    ")
+		h.writeFieldKey(buf, stack)
+		buf.WriteByte('\n')
+		buf.WriteString(stack.Value.String())
+	}
 
 	buf.WriteByte(_newline)
 
@@ -95,67 +149,175 @@ func (h *loggerHandler) Handle(ctx context.Context, r slog.Record) error {
 	return err
 }
 
-func (h *loggerHandler) writeAttrs(buf *bytes.Buffer) {
-	var (
-		errs  []slog.Attr
-		stack slog.Attr
-	)
+// writeAligned writes s with the color and the padding of the layout segment.
+func (h *loggerHandler) writeAligned(buf *bytes.Buffer, seg layoutSegment, color colorize.Color, s string) {
+	pad := seg.padding(s)
+	if seg.alignRight {
+		writePadding(buf, pad)
+	}
 
-	for _, attr := range h.attrs {
-		if attr.Key == KeyErrorsStack {
-			stack = attr
-			continue
-		}
+	h.paint(buf, color, s)
 
-		if attr.Key == KeyErr {
-			errs = extractErrors(attr.Value.Any())
-			continue
+	if !seg.alignRight {
+		writePadding(buf, pad)
+	}
+}
+
+func writePadding(buf *bytes.Buffer, n int) {
+	for i := 0; i < n; i++ {
+		buf.WriteByte(_space)
+	}
+}
+
+// writeAttrs writes the attributes of the handler and of the record,
+// and returns the stack attributes which should be written at the end of the line.
+// A nil buf only collects the stacks.
+func (h *loggerHandler) writeAttrs(buf *bytes.Buffer, r slog.Record) (stacks []slog.Attr) {
+	var errs []slog.Attr
+
+	write := h.writeAttr
+	switch {
+	case buf == nil:
+		write = func(*bytes.Buffer, slog.Attr) {}
+	case h.opt.AttrStyle == AttrStyleKeyValue:
+		write = h.writeKeyValueAttr
+	}
+
+	handle := func(attr slog.Attr) bool {
+		attr = resolveAttr(attr)
+
+		switch attr.Key {
+		case KeyErrorsStack:
+			if frames, ok := attr.Value.Any().([]StackFrame); ok {
+				attr.Value = slog.StringValue(consoleStack(frames, h.stackColor()))
+			}
+			stacks = append(stacks[:0], attr)
+		case KeyErr:
+			errs = extractErrors(attr.Value.Any(), h.stackColor())
+		default:
+			write(buf, attr)
 		}
 
-		writeAttr(buf, attr)
+		return true
 	}
 
+	for _, attr := range h.attrs {
+		handle(attr)
+	}
+	r.Attrs(handle)
+
 	for _, attr := range errs {
-		if attr.Key == KeyErrorsStack {
-			stack = attr
+		if strings.HasSuffix(attr.Key, _stackSuffix) {
+			stacks = append(stacks, attr)
 			continue
 		}
 
-		writeAttr(buf, attr)
+		write(buf, attr)
 	}
 
-	if len(stack.Key) != 0 {
-		buf.WriteByte('\n')
-		buf.WriteString("    ")
-		writeFieldKey(buf, stack)
-		buf.WriteByte('\n')
-		buf.WriteString(stack.Value.String())
-	}
+	return stacks
 }
 
-func writeAttr(buf *bytes.Buffer, attr slog.Attr) {
-	var str string
+// resolveAttr resolves the slog.LogValuer values of the attribute and of its groups.
+func resolveAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		return attr
+	}
+
+	group := attr.Value.Group()
+	attrs := make([]slog.Attr, len(group))
+	for i, a := range group {
+		attrs[i] = resolveAttr(a)
+	}
+	attr.Value = slog.GroupValue(attrs...)
+
+	return attr
+}
 
-	writeFieldKey(buf, attr)
+func (h *loggerHandler) writeAttr(buf *bytes.Buffer, attr slog.Attr) {
+	h.writeFieldKey(buf, attr)
 
 	buf.WriteByte(_space)
-	if f, ok := attrValueFunc[attr.Value.Kind()]; ok {
-		str = f(attr.Value)
-	} else {
-		str = fmt.Sprintf("%+v", attr.Value.Any())
+	h.paint(buf, h.opt.Theme.Value, attrValueString(attr.Value))
+	buf.WriteByte(_space)
+	buf.WriteByte(_space)
+}
+
+func (h *loggerHandler) writeKeyValueAttr(buf *bytes.Buffer, attr slog.Attr) {
+	h.paint(buf, h.opt.Theme.KeyColor(attr.Key), attr.Key)
+
+	buf.WriteByte('=')
+
+	str := attrValueString(attr.Value)
+	if needsQuoting(str) {
+		str = strconv.Quote(str)
 	}
 
-	colorize.Fprint(buf, colorize.ColorBlack, str)
-	buf.WriteByte(_space)
+	h.paint(buf, h.opt.Theme.Value, str)
 	buf.WriteByte(_space)
 }
 
-func writeFieldKey(buf *bytes.Buffer, attr slog.Attr) {
-	if key, ok := fieldKeyCache[attr.Key]; ok {
+func attrValueString(v slog.Value) string {
+	if f, ok := attrValueFunc[v.Kind()]; ok {
+		return f(v)
+	}
+
+	return renderAny(v.Any())
+}
+
+// needsQuoting reports whether the value of key=value attribute should be quoted.
+func needsQuoting(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+
+	for _, c := range s {
+		if c == ' ' || c == '=' || c == '"' || !unicode.IsPrint(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *loggerHandler) writeFieldKey(buf *bytes.Buffer, attr slog.Attr) {
+	if h.opt.NoColor {
+		buf.WriteString(_bracketOpen)
+		buf.WriteString(attr.Key)
+		buf.WriteString(_bracketClose)
+		return
+	}
+
+	if key, ok := h.opt.keyCache[attr.Key]; ok {
 		buf.WriteString(key)
 	} else {
-		colorize.Fprint(buf, colorize.ColorMagenta, _bracketOpen, attr.Key, _bracketClose)
+		h.paint(buf, h.opt.Theme.Key, _bracketOpen, attr.Key, _bracketClose)
+	}
+}
+
+// stackColor returns the color of the functions in the stack, it's empty when the color is disabled.
+func (h *loggerHandler) stackColor() colorize.Color {
+	if h.opt.NoColor {
+		return ""
 	}
+	return h.opt.Theme.Stack
+}
+
+// paint writes the strings wrapped with the color, the color is skipped when it's empty or disabled.
+func (h *loggerHandler) paint(buf *bytes.Buffer, color colorize.Color, str ...string) {
+	if h.opt.NoColor || len(color) == 0 {
+		for _, s := range str {
+			buf.WriteString(s)
+		}
+		return
+	}
+
+	buf.WriteString(color.String())
+	for _, s := range str {
+		buf.WriteString(s)
+	}
+	buf.WriteString(colorize.ColorReset.String())
 }
 
 var attrValueFunc = map[slog.Kind]func(slog.Value) string{
@@ -174,8 +336,14 @@ var attrValueFunc = map[slog.Kind]func(slog.Value) string{
 	slog.KindBool: func(v slog.Value) string {
 		return strconv.FormatBool(v.Bool())
 	},
+	slog.KindDuration: func(v slog.Value) string {
+		return v.Duration().String()
+	},
 	slog.KindAny: func(v slog.Value) string {
-		return fmt.Sprintf("%+v", v.Any())
+		return renderAny(v.Any())
+	},
+	slog.KindGroup: func(v slog.Value) string {
+		return renderGroup(v.Group())
 	},
 }
 
@@ -186,6 +354,7 @@ func (h *loggerHandler) clone() *loggerHandler {
 		level: h.level,
 		out:   h.out,
 		attrs: newAttrs,
+		opt:   h.opt,
 	}
 }
 
@@ -204,35 +373,31 @@ func (h *loggerHandler) WithGroup(name string) slog.Handler {
 	return h
 }
 
+const _stackSuffix = "." + KeyErrorStack
+
 const (
 	KeyErrorsStack = "error.stack"
 	KeyErrorsCause = "error.cause"
 )
 
-var (
-	fieldKeyCache = map[string]string{
-		KeyErr:         colorize.Sprint(colorize.ColorRed, _bracketOpen, KeyErr, _bracketClose),
-		KeyCtx:         colorize.Sprint(colorize.ColorCyan, _bracketOpen, KeyCtx, _bracketClose),
-		KeyFunc:        colorize.Sprint(colorize.ColorBrightBlue, _bracketOpen, KeyFunc, _bracketClose),
-		KeyErrorsCause: colorize.Sprint(colorize.ColorYellow, _bracketOpen, KeyErrorsCause, _bracketClose),
-		KeyErrorsStack: colorize.Sprint(colorize.ColorCyan, _bracketOpen, KeyErrorsStack, _bracketClose),
-	}
-)
-
 const (
-	LevelFatal int8 = 12
-	LevelError int8 = 8
-	LevelWarn  int8 = 4
-	LevelInfo  int8 = 0
-	LevelDebug int8 = -4
+	LevelFatal  int8 = 12
+	LevelError  int8 = 8
+	LevelWarn   int8 = 4
+	LevelNotice int8 = 2
+	LevelInfo   int8 = 0
+	LevelDebug  int8 = -4
+	LevelTrace  int8 = -8
 )
 
 const (
-	LevelFatalTitle = "FATAL"
-	LevelErrorTitle = "ERROR"
-	LevelWarnTitle  = "WARN "
-	LevelInfoTitle  = "INFO "
-	LevelDebugTitle = "DEBUG"
+	LevelFatalTitle  = "FATAL"
+	LevelErrorTitle  = "ERROR"
+	LevelWarnTitle   = "WARN "
+	LevelNoticeTitle = "NOTE "
+	LevelInfoTitle   = "INFO "
+	LevelDebugTitle  = "DEBUG"
+	LevelTraceTitle  = "TRACE"
 )
 
 func LevelTitle(level int8) string {
@@ -241,10 +406,3 @@ func LevelTitle(level int8) string {
 	}
 	return LevelInfoTitle
 }
-
-func LevelColor(level int8) colorize.Color {
-	if color, ok := levelColorCache[level]; ok {
-		return color
-	}
-	return colorize.ColorBrightGreen
-}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultLayout is the layout used by the console handler when no layout is provided.
+const DefaultLayout = "{time} {level} {msg}  {attrs}"
+
+const (
+	LayoutTime   = "time"
+	LayoutLevel  = "level"
+	LayoutLogger = "logger"
+	LayoutMsg    = "msg"
+	LayoutAttrs  = "attrs"
+)
+
+type layoutField int8
+
+const (
+	layoutLiteral layoutField = iota
+	layoutTime
+	layoutLevel
+	layoutLogger
+	layoutMsg
+	layoutAttrs
+)
+
+var layoutFields = map[string]layoutField{
+	LayoutTime:   layoutTime,
+	LayoutLevel:  layoutLevel,
+	LayoutLogger: layoutLogger,
+	LayoutMsg:    layoutMsg,
+	LayoutAttrs:  layoutAttrs,
+}
+
+type layoutSegment struct {
+	field      layoutField
+	literal    string
+	width      int
+	alignRight bool
+}
+
+// Layout is a compiled console layout.
+type Layout struct {
+	segments []layoutSegment
+}
+
+var defaultLayout = ParseLayout(DefaultLayout)
+
+// ParseLayout compiles a console layout.
+//
+// Placeholders are written as {name}, {name:N}, {name:<N} or {name:>N}, where N is the
+// minimum width of the field, '<' aligns the field to the left (default) and '>' to the right.
+// Unknown placeholders are kept as literal text.
+func ParseLayout(layout string) *Layout {
+	l := &Layout{}
+
+	for len(layout) != 0 {
+		open := strings.IndexByte(layout, '{')
+		if open < 0 {
+			l.appendLiteral(layout)
+			break
+		}
+
+		end := strings.IndexByte(layout[open:], '}')
+		if end < 0 {
+			l.appendLiteral(layout)
+			break
+		}
+		end += open
+
+		seg, ok := parseLayoutSegment(layout[open+1 : end])
+		if !ok {
+			l.appendLiteral(layout[:end+1])
+			layout = layout[end+1:]
+			continue
+		}
+
+		l.appendLiteral(layout[:open])
+		l.segments = append(l.segments, seg)
+		layout = layout[end+1:]
+	}
+
+	return l
+}
+
+func parseLayoutSegment(s string) (layoutSegment, bool) {
+	name, spec, hasSpec := strings.Cut(s, ":")
+	field, ok := layoutFields[name]
+	if !ok {
+		return layoutSegment{}, false
+	}
+
+	seg := layoutSegment{field: field}
+	if !hasSpec {
+		return seg, true
+	}
+
+	switch {
+	case strings.HasPrefix(spec, ">"):
+		seg.alignRight = true
+		spec = spec[1:]
+	case strings.HasPrefix(spec, "<"):
+		spec = spec[1:]
+	}
+
+	width, err := strconv.Atoi(spec)
+	if err != nil || width < 0 {
+		return layoutSegment{}, false
+	}
+	seg.width = width
+
+	return seg, true
+}
+
+func (l *Layout) appendLiteral(s string) {
+	if len(s) == 0 {
+		return
+	}
+
+	if n := len(l.segments); n != 0 && l.segments[n-1].field == layoutLiteral {
+		l.segments[n-1].literal += s
+		return
+	}
+
+	l.segments = append(l.segments, layoutSegment{field: layoutLiteral, literal: s})
+}
+
+// padding returns the number of spaces needed to fill the segment width.
+func (seg layoutSegment) padding(s string) int {
+	if seg.width == 0 {
+		return 0
+	}
+
+	if n := seg.width - utf8.RuneCountInString(s); n > 0 {
+		return n
+	}
+
+	return 0
+}
+
+// AttrStyle represents how the console handler renders attributes.
+type AttrStyle int8
+
+const (
+	// AttrStyleBracket renders attributes as `[key] value`.
+	AttrStyleBracket AttrStyle = iota
+	// AttrStyleKeyValue renders attributes as `key=value`.
+	AttrStyleKeyValue
+)
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Lazy is a value computed each time a record with it is handled.
+type Lazy func() any
+
+func (f Lazy) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+type lazyHandler struct {
+	next slog.Handler
+
+	// ops are the attributes and groups added since the first attributes with Lazy values,
+	// they are added to next for each record, with the values computed for the record.
+	ops []lazyOp
+}
+
+type lazyOp struct {
+	attrs []slog.Attr
+	group string
+}
+
+// NewLazyHandler returns a handler which computes the Lazy values once for each record,
+// so the handlers after it share the values, and the Lazy values added by WithAttrs are not
+// computed until a record is handled.
+func NewLazyHandler(next slog.Handler) slog.Handler {
+	return &lazyHandler{next: next}
+}
+
+func (h *lazyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *lazyHandler) Handle(ctx context.Context, r slog.Record) error {
+	next := h.next
+	for _, op := range h.ops {
+		if len(op.attrs) != 0 {
+			next = next.WithAttrs(resolveLazyAttrs(op.attrs))
+		} else {
+			next = next.WithGroup(op.group)
+		}
+	}
+
+	lazy := false
+	r.Attrs(func(a slog.Attr) bool {
+		lazy = hasLazy(a)
+		return !lazy
+	})
+
+	if !lazy {
+		return next.Handle(ctx, r)
+	}
+
+	rr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		rr.AddAttrs(resolveLazy(a))
+		return true
+	})
+
+	return next.Handle(ctx, rr)
+}
+
+func (h *lazyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	if len(h.ops) == 0 {
+		lazy := false
+		for _, a := range attrs {
+			if lazy = hasLazy(a); lazy {
+				break
+			}
+		}
+
+		if !lazy {
+			return &lazyHandler{next: h.next.WithAttrs(attrs)}
+		}
+	}
+
+	return &lazyHandler{next: h.next, ops: append(h.ops[:len(h.ops):len(h.ops)], lazyOp{attrs: attrs})}
+}
+
+func (h *lazyHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	if len(h.ops) == 0 {
+		return &lazyHandler{next: h.next.WithGroup(name)}
+	}
+
+	return &lazyHandler{next: h.next, ops: append(h.ops[:len(h.ops):len(h.ops)], lazyOp{group: name})}
+}
+
+// hasLazy reports whether the attribute or its groups have Lazy values.
+func hasLazy(a slog.Attr) bool {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		_, ok := a.Value.LogValuer().(Lazy)
+		return ok
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			if hasLazy(ga) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func resolveLazyAttrs(attrs []slog.Attr) []slog.Attr {
+	resolved := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		resolved[i] = resolveLazy(a)
+	}
+
+	return resolved
+}
+
+// resolveLazy computes the Lazy values of the attribute and of its groups.
+func resolveLazy(a slog.Attr) slog.Attr {
+	if !hasLazy(a) {
+		return a
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		a.Value = slog.GroupValue(resolveLazyAttrs(a.Value.Group())...)
+	} else {
+		a.Value = a.Value.Resolve()
+	}
+
+	return a
+}
@@ -0,0 +1,257 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+const (
+	// KeyTruncated is the key of the attribute added to the truncated records.
+	KeyTruncated = "truncated"
+
+	// TruncationMarker is appended to the truncated messages and values.
+	TruncationMarker = "...[truncated]"
+)
+
+// Limits are the limits of the records, zero is unlimited.
+type Limits struct {
+	MaxMessageLength   int
+	MaxAttrValueLength int
+	MaxAttrs           int
+	MaxRecordBytes     int
+}
+
+type limitHandler struct {
+	next      slog.Handler
+	limits    Limits
+	overhead  int
+	attrs     int
+	size      int
+	truncated bool
+}
+
+// NewLimitHandler returns a handler which truncates the messages and the attribute values,
+// and drops the attributes exceeding the limits, before they reach next.
+//
+// The record bytes are estimated by the lengths of the message, the keys and the values, plus overhead
+// bytes for each attribute, which are written by the format around them, e.g. the quotes of JSON.
+// The attributes are kept in order until the limits are reached. The truncated records carry the
+// attribute truncated=true.
+//
+// The slog.LogValuer values stay lazy, their lengths are limited when they are resolved,
+// but they aren't counted in the record bytes.
+func NewLimitHandler(next slog.Handler, limits Limits, overhead int) slog.Handler {
+	return &limitHandler{
+		next:     next,
+		limits:   limits,
+		overhead: overhead,
+	}
+}
+
+func (h *limitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *limitHandler) Handle(ctx context.Context, r slog.Record) error {
+	truncated := h.truncated
+
+	msg := r.Message
+	maxMsg := h.limits.MaxMessageLength
+	if h.limits.MaxRecordBytes > 0 && (maxMsg <= 0 || h.limits.MaxRecordBytes-h.size < maxMsg) {
+		maxMsg = max(h.limits.MaxRecordBytes-h.size, 1)
+	}
+	if maxMsg > 0 && len(msg) > maxMsg {
+		msg = truncateString(msg, maxMsg)
+		truncated = true
+	}
+
+	if !h.limitsAttrs() {
+		if truncated {
+			r = r.Clone()
+			r.Message = msg
+			r.AddAttrs(slog.Bool(KeyTruncated, true))
+		}
+
+		return h.next.Handle(ctx, r)
+	}
+
+	attrs, size := h.attrs, h.size+len(msg)
+
+	rr := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		a, ok, t := h.limitAttr(a, attrs, size)
+		truncated = truncated || t
+		if !ok {
+			return false
+		}
+
+		attrs++
+		size += h.attrSize(a)
+		rr.AddAttrs(a)
+		return true
+	})
+
+	if truncated {
+		rr.AddAttrs(slog.Bool(KeyTruncated, true))
+	}
+
+	return h.next.Handle(ctx, rr)
+}
+
+// limitsAttrs reports whether the attributes are limited, otherwise they are passed as is.
+func (h *limitHandler) limitsAttrs() bool {
+	return h.limits.MaxAttrs > 0 || h.limits.MaxAttrValueLength > 0 || h.limits.MaxRecordBytes > 0
+}
+
+// attrSize estimates the bytes of the attribute only when MaxRecordBytes is set, since it formats the values.
+func (h *limitHandler) attrSize(a slog.Attr) int {
+	if h.limits.MaxRecordBytes <= 0 {
+		return 0
+	}
+
+	return attrSize(a, h.overhead)
+}
+
+// limitAttr truncates the value of the attribute, and reports false when the attribute should be dropped
+// because there are already n attributes of the size. It also reports whether anything is truncated.
+func (h *limitHandler) limitAttr(a slog.Attr, n, size int) (slog.Attr, bool, bool) {
+	if h.limits.MaxAttrs > 0 && n >= h.limits.MaxAttrs {
+		return a, false, true
+	}
+
+	a, truncated := h.truncateAttr(a)
+	if h.limits.MaxRecordBytes > 0 && size+attrSize(a, h.overhead) > h.limits.MaxRecordBytes {
+		return a, false, true
+	}
+
+	return a, true, truncated
+}
+
+// truncateAttr truncates the value of the attribute to MaxAttrValueLength, and reports whether it's truncated.
+func (h *limitHandler) truncateAttr(a slog.Attr) (slog.Attr, bool) {
+	maxLen := h.limits.MaxAttrValueLength
+	if maxLen <= 0 {
+		return a, false
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		a.Value = slog.AnyValue(truncatedValuer{handler: h, valuer: a.Value.LogValuer()})
+	case slog.KindString:
+		if s := a.Value.String(); len(s) > maxLen {
+			a.Value = slog.StringValue(truncateString(s, maxLen))
+			return a, true
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		truncated := false
+		for i, ga := range group {
+			var t bool
+			attrs[i], t = h.truncateAttr(ga)
+			truncated = truncated || t
+		}
+		a.Value = slog.GroupValue(attrs...)
+		return a, truncated
+	case slog.KindAny:
+		if _, ok := a.Value.Any().(error); ok {
+			// keep the error, so its stack can still be extracted
+			return a, false
+		}
+		if s := fmt.Sprintf("%+v", a.Value.Any()); len(s) > maxLen {
+			a.Value = slog.StringValue(truncateString(s, maxLen))
+			return a, true
+		}
+	}
+
+	return a, false
+}
+
+type truncatedValuer struct {
+	handler *limitHandler
+	valuer  slog.LogValuer
+}
+
+func (v truncatedValuer) LogValue() slog.Value {
+	a, _ := v.handler.truncateAttr(slog.Attr{Value: v.valuer.LogValue()})
+	return a.Value
+}
+
+// attrSize estimates the bytes of the attribute, with the overhead bytes of the format for it
+// and for each attribute of its groups.
+func attrSize(a slog.Attr, overhead int) int {
+	size := len(a.Key) + overhead
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		return size
+	case slog.KindString:
+		return size + len(a.Value.String())
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			size += attrSize(ga, overhead)
+		}
+		return size
+	case slog.KindAny:
+		return size + len(fmt.Sprintf("%+v", a.Value.Any()))
+	default:
+		return size + len(a.Value.String())
+	}
+}
+
+// truncateString cuts s to at most n bytes on a rune boundary, and appends the marker.
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + TruncationMarker
+}
+
+func (h *limitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	if !h.limitsAttrs() {
+		hh := *h
+		hh.next = h.next.WithAttrs(attrs)
+		return &hh
+	}
+
+	hh := &limitHandler{
+		limits:    h.limits,
+		overhead:  h.overhead,
+		attrs:     h.attrs,
+		size:      h.size,
+		truncated: h.truncated,
+	}
+
+	kept := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		a, ok, t := hh.limitAttr(a, hh.attrs, hh.size)
+		hh.truncated = hh.truncated || t
+		if !ok {
+			break
+		}
+
+		hh.attrs++
+		hh.size += hh.attrSize(a)
+		kept = append(kept, a)
+	}
+
+	hh.next = h.next.WithAttrs(kept)
+	return hh
+}
+
+func (h *limitHandler) WithGroup(name string) slog.Handler {
+	return &limitHandler{
+		next:      h.next.WithGroup(name),
+		limits:    h.limits,
+		overhead:  h.overhead,
+		attrs:     h.attrs,
+		size:      h.size,
+		truncated: h.truncated,
+	}
+}
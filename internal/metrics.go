@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// MetricsLevels are the levels counted by the metrics, in the order of the counters.
+var MetricsLevels = [...]int8{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal}
+
+// Metrics are the counters of the loggers sharing a name.
+type Metrics struct {
+	Emitted     [len(MetricsLevels)]atomic.Uint64
+	Dropped     [len(MetricsLevels)]atomic.Uint64
+	WriteErrors [len(MetricsLevels)]atomic.Uint64
+	Bytes       atomic.Uint64
+}
+
+// metricsIndex returns the index of the counters of the level,
+// custom levels are counted as the nearest level below them.
+func metricsIndex(level slog.Level) int {
+	for i := len(MetricsLevels) - 1; i > 0; i-- {
+		if level >= slog.Level(MetricsLevels[i]) {
+			return i
+		}
+	}
+
+	return 0
+}
+
+type metricsHandler struct {
+	next    slog.Handler
+	metrics *Metrics
+}
+
+// NewMetricsHandler returns a handler which counts the records dropped by the level of next,
+// the records handled by next and the errors returned by next.
+func NewMetricsHandler(next slog.Handler, m *Metrics) slog.Handler {
+	return &metricsHandler{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.next.Enabled(ctx, level) {
+		return true
+	}
+
+	h.metrics.Dropped[metricsIndex(level)].Add(1)
+	return false
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	i := metricsIndex(r.Level)
+	if err := h.next.Handle(ctx, r); err != nil {
+		h.metrics.WriteErrors[i].Add(1)
+		return err
+	}
+
+	h.metrics.Emitted[i].Add(1)
+	return nil
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &metricsHandler{next: h.next.WithAttrs(attrs), metrics: h.metrics}
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	return &metricsHandler{next: h.next.WithGroup(name), metrics: h.metrics}
+}
+
+type countingWriter struct {
+	w       io.Writer
+	metrics *Metrics
+}
+
+// NewCountingWriter returns a writer which counts the bytes written to w.
+func NewCountingWriter(w io.Writer, m *Metrics) io.Writer {
+	return &countingWriter{w: w, metrics: m}
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.metrics.Bytes.Add(uint64(n))
+	return n, err
+}
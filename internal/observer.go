@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+)
+
+type observerHandler struct {
+	next    slog.Handler
+	attrs   []slog.Attr
+	groups  []string
+	observe func(ctx context.Context, r slog.Record, attrs []slog.Attr)
+}
+
+// NewObserverHandler returns a handler which calls observe with each record before it's handled by next.
+//
+// The attributes given to observe are the ones of the handler followed by the ones of the record,
+// groups become group attributes.
+func NewObserverHandler(next slog.Handler, observe func(ctx context.Context, r slog.Record, attrs []slog.Attr)) slog.Handler {
+	return &observerHandler{
+		next:    next,
+		observe: observe,
+	}
+}
+
+func (h *observerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *observerHandler) Handle(ctx context.Context, r slog.Record) error {
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0 && len(recordAttrs) != 0; i-- {
+		recordAttrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(recordAttrs...)}}
+	}
+
+	attrs := make([]slog.Attr, 0, len(h.attrs)+len(recordAttrs))
+	attrs = append(attrs, h.attrs...)
+	attrs = append(attrs, recordAttrs...)
+	h.observe(ctx, r, attrs)
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *observerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	grouped := attrs
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		grouped = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(grouped...)}}
+	}
+
+	return &observerHandler{
+		next:    h.next.WithAttrs(attrs),
+		attrs:   append(h.attrs[:len(h.attrs):len(h.attrs)], grouped...),
+		groups:  h.groups,
+		observe: h.observe,
+	}
+}
+
+func (h *observerHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	return &observerHandler{
+		next:    h.next.WithGroup(name),
+		attrs:   h.attrs,
+		groups:  append(h.groups[:len(h.groups):len(h.groups)], name),
+		observe: h.observe,
+	}
+}
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// _renderMaxDepth is the depth of the nested values rendered before they're elided.
+	_renderMaxDepth = 5
+	// _renderMaxItems is the number of the elements of a map, slice or struct rendered before the rest are elided.
+	_renderMaxItems = 32
+)
+
+// isStructured reports whether the value is rendered by renderValue instead of fmt.
+func isStructured(x any) bool {
+	switch x.(type) {
+	case nil, error, fmt.Stringer, fmt.Formatter, []byte:
+		return false
+	}
+
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// renderAny renders maps, slices and structs in a compact JSON-like form, e.g. {a: 1, b: ["x", "y"]},
+// where the keys of the maps are sorted and the fields of the structs respect their json tags.
+// Other values are formatted with %+v.
+func renderAny(x any) string {
+	if !isStructured(x) {
+		return fmt.Sprintf("%+v", x)
+	}
+
+	b := &strings.Builder{}
+	renderValue(b, reflect.ValueOf(x), 0)
+	return b.String()
+}
+
+// renderGroup renders the attributes of a group in the same form as renderAny.
+func renderGroup(attrs []slog.Attr) string {
+	b := &strings.Builder{}
+	renderAttrs(b, attrs, 0)
+	return b.String()
+}
+
+func renderAttrs(b *strings.Builder, attrs []slog.Attr, depth int) {
+	if depth >= _renderMaxDepth {
+		b.WriteString("{...}")
+		return
+	}
+
+	b.WriteByte('{')
+	for i, a := range attrs {
+		if i == _renderMaxItems {
+			writeElided(b, len(attrs)-i)
+			break
+		}
+
+		if i != 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(a.Key)
+		b.WriteString(": ")
+		renderSlogValue(b, a.Value.Resolve(), depth+1)
+	}
+	b.WriteByte('}')
+}
+
+func renderSlogValue(b *strings.Builder, v slog.Value, depth int) {
+	switch v.Kind() {
+	case slog.KindString:
+		b.WriteString(strconv.Quote(v.String()))
+	case slog.KindGroup:
+		renderAttrs(b, v.Group(), depth)
+	case slog.KindAny:
+		renderValue(b, reflect.ValueOf(v.Any()), depth)
+	default:
+		b.WriteString(v.String())
+	}
+}
+
+func renderValue(b *strings.Builder, v reflect.Value, depth int) {
+	if !v.IsValid() {
+		b.WriteString("<nil>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case error:
+			b.WriteString(strconv.Quote(x.Error()))
+			return
+		case fmt.Stringer:
+			b.WriteString(strconv.Quote(x.String()))
+			return
+		case []byte:
+			if utf8.Valid(x) {
+				b.WriteString(strconv.Quote(string(x)))
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		renderValue(b, v.Elem(), depth)
+	case reflect.String:
+		b.WriteString(strconv.Quote(v.String()))
+	case reflect.Map:
+		renderMap(b, v, depth)
+	case reflect.Slice, reflect.Array:
+		renderSlice(b, v, depth)
+	case reflect.Struct:
+		renderStruct(b, v, depth)
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	default:
+		b.WriteString(v.Type().String())
+	}
+}
+
+func renderMap(b *strings.Builder, v reflect.Value, depth int) {
+	if depth >= _renderMaxDepth {
+		b.WriteString("{...}")
+		return
+	}
+
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		if key.Kind() == reflect.String {
+			entries = append(entries, entry{key: key.String(), value: iter.Value()})
+		} else {
+			entries = append(entries, entry{key: fmt.Sprint(key.Interface()), value: iter.Value()})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	b.WriteByte('{')
+	for i, e := range entries {
+		if i == _renderMaxItems {
+			writeElided(b, len(entries)-i)
+			break
+		}
+
+		if i != 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(e.key)
+		b.WriteString(": ")
+		renderValue(b, e.value, depth+1)
+	}
+	b.WriteByte('}')
+}
+
+func renderSlice(b *strings.Builder, v reflect.Value, depth int) {
+	if depth >= _renderMaxDepth {
+		b.WriteString("[...]")
+		return
+	}
+
+	b.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i == _renderMaxItems {
+			writeElided(b, v.Len()-i)
+			break
+		}
+
+		if i != 0 {
+			b.WriteString(", ")
+		}
+
+		renderValue(b, v.Index(i), depth+1)
+	}
+	b.WriteByte(']')
+}
+
+func renderStruct(b *strings.Builder, v reflect.Value, depth int) {
+	if depth >= _renderMaxDepth {
+		b.WriteString("{...}")
+		return
+	}
+
+	t := v.Type()
+	n := 0
+
+	b.WriteByte('{')
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		omitEmpty := false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, opts, _ := strings.Cut(tag, ",")
+			if tagName == "-" && len(opts) == 0 {
+				continue
+			}
+			if len(tagName) != 0 {
+				name = tagName
+			}
+			omitEmpty = strings.Contains(","+opts+",", ",omitempty,")
+		}
+
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if n == _renderMaxItems {
+			writeElided(b, t.NumField()-i)
+			break
+		}
+
+		if n != 0 {
+			b.WriteString(", ")
+		}
+		n++
+
+		b.WriteString(name)
+		b.WriteString(": ")
+		renderValue(b, fv, depth+1)
+	}
+	b.WriteByte('}')
+}
+
+func writeElided(b *strings.Builder, n int) {
+	b.WriteString(", ...+")
+	b.WriteString(strconv.Itoa(n))
+}
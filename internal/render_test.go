@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type renderUser struct {
+	Name    string            `json:"name"`
+	Email   string            `json:"email,omitempty"`
+	Secret  string            `json:"-"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]int    `json:"labels"`
+	Parent  *renderUser       `json:"parent,omitempty"`
+	Extra   map[string]string `json:",omitempty"`
+	private int
+}
+
+func TestRenderAny(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    any
+		expected string
+	}{
+		{"map", map[string]any{"foo": 123, "bar": "456"}, `{bar: "456", foo: 123}`},
+		{"slice", []any{1, "a", nil, true}, `[1, "a", <nil>, true]`},
+		{"struct", renderUser{Name: "alice", Tags: []string{"x"}, Labels: map[string]int{"b": 2, "a": 1}}, `{name: "alice", tags: ["x"], labels: {a: 1, b: 2}}`},
+		{"pointer", &renderUser{Name: "bob", Parent: &renderUser{Name: "alice"}}, `{name: "bob", tags: <nil>, labels: <nil>, parent: {name: "alice", tags: <nil>, labels: <nil>}}`},
+		{"error", errors.New("failed"), "failed"},
+		{"nested error", []error{errors.New("failed")}, `["failed"]`},
+		{"bytes", []byte("raw"), "[114 97 119]"},
+		{"scalar", 3.5, "3.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := renderAny(tc.value); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderLimits(t *testing.T) {
+	long := make([]int, 100)
+	if got := renderAny(long); !strings.HasSuffix(got, ", ...+68]") {
+		t.Errorf("expected elided slice, got %s", got)
+	}
+
+	var nested any = "leaf"
+	for i := 0; i < 10; i++ {
+		nested = []any{nested}
+	}
+	if got := renderAny(nested); !strings.Contains(got, "[...]") || strings.Contains(got, "leaf") {
+		t.Errorf("expected elided depth, got %s", got)
+	}
+
+	group := slog.GroupValue(slog.String("name", "alice"), slog.Int("age", 20))
+	if got := renderGroup(group.Group()); got != `{name: "alice", age: 20}` {
+		t.Errorf("unexpected group rendering: %s", got)
+	}
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+)
+
+type replaceHandler struct {
+	next    slog.Handler
+	replace func(a slog.Attr) slog.Attr
+}
+
+// NewReplaceHandler returns a handler which replaces the attributes of the handler
+// and of the records with replace before they reach next.
+func NewReplaceHandler(next slog.Handler, replace func(a slog.Attr) slog.Attr) slog.Handler {
+	return &replaceHandler{
+		next:    next,
+		replace: replace,
+	}
+}
+
+func (h *replaceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *replaceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.NumAttrs() == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	rr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		rr.AddAttrs(h.replace(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, rr)
+}
+
+func (h *replaceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replaced := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		replaced[i] = h.replace(a)
+	}
+
+	return &replaceHandler{next: h.next.WithAttrs(replaced), replace: h.replace}
+}
+
+func (h *replaceHandler) WithGroup(name string) slog.Handler {
+	return &replaceHandler{next: h.next.WithGroup(name), replace: h.replace}
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+)
+
+type samplingHandler struct {
+	next  slog.Handler
+	level slog.Level
+	keep  func(ctx context.Context) bool
+}
+
+// NewSamplingHandler returns a handler which drops the records at or below level
+// when keep reports false for the context of the record.
+func NewSamplingHandler(next slog.Handler, level int8, keep func(ctx context.Context) bool) slog.Handler {
+	return &samplingHandler{
+		next:  next,
+		level: slog.Level(level),
+		keep:  keep,
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level <= h.level && !h.keep(ctx) {
+		return false
+	}
+
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), level: h.level, keep: h.keep}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), level: h.level, keep: h.keep}
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// DefaultStackDepth is the number of frames captured when the depth isn't specified.
+const DefaultStackDepth = 32
+
+// _stackInternal are the prefixes of the functions of the logging call, skipped at the top of the stack.
+var _stackInternal = []string{"runtime.", "log/slog.", "github.com/yanun0323/logs.", "github.com/yanun0323/logs/internal."}
+
+type stackHandler struct {
+	next  slog.Handler
+	level slog.Level
+	depth int
+	skip  []string
+
+	// hasStack reports whether the attributes of the handler carry the stack already,
+	// grouped reports whether the later attributes are in a group.
+	hasStack bool
+	grouped  bool
+}
+
+// NewStackHandler returns a handler which adds the call stack to the records at or above level
+// as the attribute "error.stack" of []StackFrame.
+//
+// The records carrying the attribute already, e.g. the recovered panics, are kept as is.
+// The frames of the logging call are skipped, so are the frames of the functions starting with
+// any of skip. At most depth frames are kept, DefaultStackDepth is used when it's not positive.
+func NewStackHandler(next slog.Handler, level int8, depth int, skip []string) slog.Handler {
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+
+	return &stackHandler{
+		next:  next,
+		level: slog.Level(level),
+		depth: depth,
+		skip:  skip,
+	}
+}
+
+func (h *stackHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *stackHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level || h.hasStack || (!h.grouped && hasStackAttr(r)) {
+		return h.next.Handle(ctx, r)
+	}
+
+	r = r.Clone()
+	r.AddAttrs(slog.Any(KeyErrorsStack, CaptureStack(h.depth, h.skip)))
+
+	return h.next.Handle(ctx, r)
+}
+
+// CaptureStack returns at most depth frames of the call stack of the caller, the frames of
+// the logging call or the panic at the top are skipped, so are the frames of the functions
+// starting with any of skip.
+func CaptureStack(depth int, skip []string) []StackFrame {
+	// the logging call takes about a dozen frames
+	pcs := make([]uintptr, depth+32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	result := make([]StackFrame, 0, depth)
+	caller := false
+	for len(result) < depth {
+		frame, more := frames.Next()
+
+		if !caller && !isStackInternal(frame) {
+			caller = true
+		}
+
+		if caller && frame.Function != "runtime.goexit" && !hasAnyPrefix(frame.Function, skip) {
+			result = append(result, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// isStackInternal reports whether the frame belongs to the logging call,
+// the tests of the package are not.
+func isStackInternal(frame runtime.Frame) bool {
+	return hasAnyPrefix(frame.Function, _stackInternal) && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasStackAttr(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == KeyErrorsStack
+		return !found
+	})
+
+	return found
+}
+
+func (h *stackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hh := *h
+	hh.next = h.next.WithAttrs(attrs)
+
+	for _, a := range attrs {
+		if !h.grouped && a.Key == KeyErrorsStack {
+			hh.hasStack = true
+		}
+	}
+
+	return &hh
+}
+
+func (h *stackHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	hh := *h
+	hh.next = h.next.WithGroup(name)
+	hh.grouped = true
+
+	return &hh
+}
@@ -0,0 +1,67 @@
+package internal
+
+import "github.com/yanun0323/logs/internal/colorize"
+
+// Theme is the palette of the console handler. An empty color writes the text without color.
+type Theme struct {
+	Time    colorize.Color
+	Message colorize.Color
+	Key     colorize.Color
+	Value   colorize.Color
+	Stack   colorize.Color
+	Levels  map[int8]colorize.Color
+	Keys    map[string]colorize.Color
+}
+
+// DefaultTheme is the palette used when no theme is provided.
+var DefaultTheme = &Theme{
+	Time:  colorize.ColorBlack,
+	Key:   colorize.ColorMagenta,
+	Value: colorize.ColorBlack,
+	Stack: colorize.ColorBrightBlue,
+	Levels: map[int8]colorize.Color{
+		LevelTrace:  colorize.ColorBrightBlack,
+		LevelDebug:  colorize.ColorBlue,
+		LevelInfo:   colorize.ColorGreen,
+		LevelNotice: colorize.ColorCyan,
+		LevelWarn:   colorize.ColorYellow,
+		LevelError:  colorize.ColorRed,
+		LevelFatal:  colorize.ColorRedReversed,
+	},
+	Keys: map[string]colorize.Color{
+		KeyErr:         colorize.ColorRed,
+		KeyCtx:         colorize.ColorCyan,
+		KeyFunc:        colorize.ColorBrightBlue,
+		KeyErrorsCause: colorize.ColorYellow,
+		KeyErrorsStack: colorize.ColorCyan,
+	},
+}
+
+// LevelColor returns the color of the level.
+func (t *Theme) LevelColor(level int8) colorize.Color {
+	if color, ok := t.Levels[level]; ok {
+		return color
+	}
+	return colorize.ColorBrightGreen
+}
+
+// KeyColor returns the color of the attribute key.
+func (t *Theme) KeyColor(key string) colorize.Color {
+	if color, ok := t.Keys[key]; ok {
+		return color
+	}
+	return t.Key
+}
+
+// keyCache pre-renders the bracketed keys which have their own color.
+func (t *Theme) keyCache() map[string]string {
+	cache := make(map[string]string, len(t.Keys))
+	for key, color := range t.Keys {
+		if len(color) == 0 {
+			cache[key] = _bracketOpen + key + _bracketClose
+			continue
+		}
+		cache[key] = colorize.Sprint(color, _bracketOpen, key, _bracketClose)
+	}
+	return cache
+}
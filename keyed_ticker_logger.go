@@ -0,0 +1,299 @@
+package logs
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultTickerKeyCapacity is the default number of keys tracked by a keyed ticker logger.
+const defaultTickerKeyCapacity = 1024
+
+// TickerKey represents how a keyed ticker logger groups the messages into gates.
+type TickerKey int8
+
+const (
+	// TickerKeyMessage groups the messages by level and message template,
+	// which is the format of the 'f' methods or the message of the others.
+	TickerKeyMessage TickerKey = iota
+
+	// TickerKeyCaller groups the messages by level and the caller site.
+	TickerKeyCaller
+)
+
+// KeyedTickerLogger is a ticker logger which tracks the interval for each key separately.
+type KeyedTickerLogger interface {
+	TickerLogger
+
+	// WithTickerKey copies the logger and makes its messages of the same level share the gate
+	// of the key, regardless of the TickerKey of the logger. Each level of the key has its own gate.
+	WithTickerKey(key string) KeyedTickerLogger
+}
+
+type keyedTickerLogger struct {
+	gates *tickerGates
+	by    TickerKey
+	key   string
+
+	Logger
+}
+
+// NewKeyedTickerLogger creates a new keyed ticker logger with the given interval and level.
+//
+// A keyed ticker logger logs a message only when the interval of its key has passed,
+// so distinct events don't suppress each other. The key is derived as specified by 'by',
+// or given explicitly via WithTickerKey.
+//
+// At most 'capacity' keys are tracked, the least recently used key is evicted when it's exceeded.
+// The keys and the counters are shared with the loggers derived by With.
+//
+// Like NewTickerLogger, the logged message carries the suppressed fields of its key.
+//
+// If option is not provided, the logger will write to the os.Stdout with console format.
+func NewKeyedTickerLogger(level Level, interval time.Duration, by TickerKey, capacity int, option ...*Option) KeyedTickerLogger {
+	if capacity <= 0 {
+		capacity = defaultTickerKeyCapacity
+	}
+
+	return &keyedTickerLogger{
+		gates: &tickerGates{
+			interval: interval,
+			capacity: capacity,
+			list:     list.New(),
+			items:    make(map[tickerGateKey]*list.Element, capacity),
+			counter:  &tickerCounter{},
+		},
+		by:     by,
+		Logger: New(level, option...),
+	}
+}
+
+// fire returns the logger to log the message with, and false when the message should be dropped.
+//
+// It must be called directly by the logging methods, so that the caller site can be found.
+func (l *keyedTickerLogger) fire(level Level, template string, args []any) (Logger, bool) {
+	key := tickerGateKey{level: level}
+
+	switch {
+	case len(l.key) != 0:
+		key.key = l.key
+	case l.by == TickerKeyCaller:
+		key.pc, _, _, _ = runtime.Caller(2)
+	case len(template) != 0 || len(args) == 0:
+		key.key = template
+	default:
+		if str, ok := args[0].(string); ok && len(args) == 1 {
+			key.key = str
+		} else {
+			key.key = fmt.Sprint(args...)
+		}
+	}
+
+	return l.gates.get(key).fire(l.Logger)
+}
+
+func (l *keyedTickerLogger) Stats() TickerStats {
+	return l.gates.counter.stats()
+}
+
+func (l *keyedTickerLogger) clone(logger Logger) *keyedTickerLogger {
+	return &keyedTickerLogger{
+		gates:  l.gates,
+		by:     l.by,
+		key:    l.key,
+		Logger: logger,
+	}
+}
+
+func (l *keyedTickerLogger) Copy() Logger {
+	return l.clone(l.Logger.Copy())
+}
+
+func (l *keyedTickerLogger) WithTickerKey(key string) KeyedTickerLogger {
+	ll := l.clone(l.Logger)
+	ll.key = key
+	return ll
+}
+
+func (l *keyedTickerLogger) WithError(err error) Logger {
+	return l.With(KeyErr, err)
+}
+
+func (l *keyedTickerLogger) WithFunc(function string) Logger {
+	return l.clone(l.Logger.WithFunc(function))
+}
+
+func (l *keyedTickerLogger) AppendFunc(function string) Logger {
+	return l.clone(l.Logger.AppendFunc(function))
+}
+
+func (l *keyedTickerLogger) AppendCaller() Logger {
+	return l.AppendFunc(callerFunc(1))
+}
+
+func (l *keyedTickerLogger) WithCtx(ctx context.Context) Logger {
+	return l.clone(l.Logger.WithCtx(ctx))
+}
+
+func (l *keyedTickerLogger) With(args ...any) Logger {
+	return l.clone(l.Logger.With(args...))
+}
+
+func (l *keyedTickerLogger) Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logAttachKey, l)
+}
+
+func (l *keyedTickerLogger) bindContext(ctx context.Context) Logger {
+	return l.clone(bindContext(l.Logger, ctx))
+}
+
+func (l *keyedTickerLogger) Log(level Level, args ...any) {
+	if logger, ok := l.fire(level, "", args); ok {
+		logger.Log(level, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Logf(level Level, format string, args ...any) {
+	if logger, ok := l.fire(level, format, nil); ok {
+		logger.Logf(level, format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Trace(args ...any) {
+	if logger, ok := l.fire(LevelTrace, "", args); ok {
+		logger.Trace(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Tracef(format string, args ...any) {
+	if logger, ok := l.fire(LevelTrace, format, nil); ok {
+		logger.Tracef(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Debug(args ...any) {
+	if logger, ok := l.fire(LevelDebug, "", args); ok {
+		logger.Debug(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Debugf(format string, args ...any) {
+	if logger, ok := l.fire(LevelDebug, format, nil); ok {
+		logger.Debugf(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Info(args ...any) {
+	if logger, ok := l.fire(LevelInfo, "", args); ok {
+		logger.Info(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Infof(format string, args ...any) {
+	if logger, ok := l.fire(LevelInfo, format, nil); ok {
+		logger.Infof(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Notice(args ...any) {
+	if logger, ok := l.fire(LevelNotice, "", args); ok {
+		logger.Notice(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Noticef(format string, args ...any) {
+	if logger, ok := l.fire(LevelNotice, format, nil); ok {
+		logger.Noticef(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Warn(args ...any) {
+	if logger, ok := l.fire(LevelWarn, "", args); ok {
+		logger.Warn(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Warnf(format string, args ...any) {
+	if logger, ok := l.fire(LevelWarn, format, nil); ok {
+		logger.Warnf(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Error(args ...any) {
+	if logger, ok := l.fire(LevelError, "", args); ok {
+		logger.Error(args...)
+	}
+}
+
+func (l *keyedTickerLogger) Errorf(format string, args ...any) {
+	if logger, ok := l.fire(LevelError, format, nil); ok {
+		logger.Errorf(format, args...)
+	}
+}
+
+func (l *keyedTickerLogger) Fatal(args ...any) {
+	l.Logger.Fatal(args...)
+}
+
+func (l *keyedTickerLogger) Fatalf(format string, args ...any) {
+	l.Logger.Fatalf(format, args...)
+}
+
+func (l *keyedTickerLogger) RecoverPanic() {
+	if p := recover(); p != nil {
+		logPanic(l.Logger, p)
+	}
+}
+
+func (l *keyedTickerLogger) TraceFunc() func() {
+	return traceFunc(l.AppendFunc(callerFunc(1)))
+}
+
+func (l *keyedTickerLogger) Start(name string) *Timer {
+	return newTimer(l, name)
+}
+
+type tickerGateKey struct {
+	level Level
+	key   string
+	pc    uintptr
+}
+
+type tickerGateItem struct {
+	key  tickerGateKey
+	gate *tickerGate
+}
+
+// tickerGates is a bounded LRU of ticker gates.
+type tickerGates struct {
+	mu       sync.Mutex
+	interval time.Duration
+	capacity int
+	list     *list.List
+	items    map[tickerGateKey]*list.Element
+	counter  *tickerCounter
+}
+
+func (g *tickerGates) get(key tickerGateKey) *tickerGate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if elem, ok := g.items[key]; ok {
+		g.list.MoveToFront(elem)
+		return elem.Value.(*tickerGateItem).gate
+	}
+
+	if g.list.Len() >= g.capacity {
+		oldest := g.list.Back()
+		g.list.Remove(oldest)
+		delete(g.items, oldest.Value.(*tickerGateItem).key)
+	}
+
+	item := &tickerGateItem{key: key, gate: newTickerGate(g.interval, g.counter)}
+	g.items[key] = g.list.PushFront(item)
+
+	return item.gate
+}
@@ -0,0 +1,60 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyedTickerLogger(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewKeyedTickerLogger(LevelDebug, time.Hour, TickerKeyMessage, 0, &Option{Output: writer})
+
+	for i := 0; i < 10; i++ {
+		l.Info("noisy")
+		l.Infof("noisy %d", i)
+	}
+	l.Error("unrelated")
+	l.With("key", "value").Info("noisy")
+
+	if line := strings.Count(writer.String(), "\n"); line != 3 {
+		t.Errorf("Expected 3 lines, but got %d lines: %s", line, writer.String())
+	}
+
+	writer.Reset()
+	l = NewKeyedTickerLogger(LevelDebug, time.Hour, TickerKeyCaller, 0, &Option{Output: writer})
+	for i := 0; i < 10; i++ {
+		l.Infof("first %d", i)
+		l.Infof("second %d", i)
+	}
+
+	if line := strings.Count(writer.String(), "\n"); line != 2 {
+		t.Errorf("Expected 2 lines, but got %d lines: %s", line, writer.String())
+	}
+
+	writer.Reset()
+	keyed := l.WithTickerKey("shared")
+	keyed.Info("a")
+	keyed.Error("b")
+	keyed.WithTickerKey("other").Info("c")
+
+	if line := strings.Count(writer.String(), "\n"); line != 3 {
+		t.Errorf("Expected 3 lines, but got %d lines: %s", line, writer.String())
+	}
+}
+
+func TestKeyedTickerLoggerCapacity(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewKeyedTickerLogger(LevelDebug, time.Hour, TickerKeyMessage, 2, &Option{Output: writer})
+
+	l.Info("a")
+	l.Info("b")
+	l.Info("c") // evicts "a"
+	l.Info("a")
+	l.Info("c")
+
+	if line := strings.Count(writer.String(), "\n"); line != 4 {
+		t.Errorf("Expected 4 lines, but got %d lines: %s", line, writer.String())
+	}
+}
@@ -0,0 +1,21 @@
+package logs
+
+import "github.com/yanun0323/logs/internal"
+
+// DefaultLayout is the line layout used by FormatConsole when Option.Layout is empty.
+//
+// Available placeholders: {time}, {level}, {logger}, {msg} and {attrs}.
+const DefaultLayout = internal.DefaultLayout
+
+// AttrStyle represents how FormatConsole renders attributes.
+type AttrStyle int8
+
+const (
+	// AttrStyleBracket renders attributes as `[key] value`.
+	// This is the default style.
+	AttrStyleBracket AttrStyle = AttrStyle(internal.AttrStyleBracket)
+
+	// AttrStyleKeyValue renders attributes as `key=value`,
+	// values containing spaces are quoted.
+	AttrStyleKeyValue AttrStyle = AttrStyle(internal.AttrStyleKeyValue)
+)
@@ -0,0 +1,42 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/logs/internal/colorize"
+)
+
+func TestLayout(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Output:    writer,
+		Name:      "api",
+		Layout:    "{level:>6} [{logger}] {msg:8}| {attrs}{unknown}",
+		AttrStyle: AttrStyleKeyValue,
+	})
+
+	l.With("user", "i'm user", "id", 1).Warn("hello")
+
+	want := "  WARN [api] hello   | user=\"i'm user\" id=1 {unknown}\n"
+	if got := colorize.Reset(writer.String()); got != want {
+		t.Errorf("unexpected output:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestLayoutWithoutAttrs(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{Output: writer, Layout: "{level} {msg}", Color: ColorModeNever})
+
+	l.With("user", "i'm user", KeyErrorsStack, []Frame{{Function: "main.main", File: "main.go", Line: 3}}).Error("hello")
+
+	got := writer.String()
+	if strings.Contains(got, "i'm user") {
+		t.Errorf("expected the attributes to be omitted: %q", got)
+	}
+
+	if !strings.Contains(got, "[main.main] main.go:3") {
+		t.Errorf("expected the stack to be written: %q", got)
+	}
+}
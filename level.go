@@ -1,7 +1,9 @@
 package logs
 
 import (
+	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 )
 
@@ -17,20 +19,72 @@ func (level Level) String() string {
 		return "error"
 	case LevelWarn:
 		return "warn"
+	case LevelNotice:
+		return "notice"
 	case LevelInfo:
 		return "info"
 	case LevelDebug:
 		return "debug"
+	case LevelTrace:
+		return "trace"
 	}
 
 	return "panic"
 }
 
+// MarshalText implements encoding.TextMarshaler, so the level is encoded as its name,
+// or as its number when it's not one of the named levels.
+func (level Level) MarshalText() ([]byte, error) {
+	switch level {
+	case LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal:
+		return []byte(level.String()), nil
+	}
+
+	return strconv.AppendInt(nil, int64(level), 10), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it accepts the names of NewLevel and numbers.
+func (level *Level) UnmarshalText(text []byte) error {
+	s := string(text)
+	if n, err := strconv.ParseInt(s, 10, 8); err == nil {
+		*level = Level(n)
+		return nil
+	}
+
+	lvl := NewLevel(s)
+	if lvl == LevelFatal && !strings.EqualFold(s, "fatal") {
+		return fmt.Errorf("invalid level: %q", s)
+	}
+
+	*level = lvl
+	return nil
+}
+
+// replaceLevel renders the level of FormatText and FormatJSON as the upper case name of Level,
+// e.g. "TRACE" rather than "DEBUG-4" of slog.
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) != 0 || a.Key != slog.LevelKey {
+		return a
+	}
+
+	lvl, ok := a.Value.Any().(slog.Level)
+	if !ok {
+		return a
+	}
+
+	switch level := Level(lvl); level {
+	case LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal:
+		a.Value = slog.StringValue(strings.ToUpper(level.String()))
+	}
+
+	return a
+}
+
 // NewLevel takes a string level and returns the Logs log level constant.
 //
 // return panic level when there's no matched string
 //
-// allowed args: "fatal", "error", "warn", "warning", "info", "debug"
+// allowed args: "fatal", "error", "warn", "warning", "notice", "info", "debug", "trace"
 func NewLevel(lvl string) Level {
 	switch strings.ToLower(lvl) {
 	case "fatal":
@@ -39,10 +93,14 @@ func NewLevel(lvl string) Level {
 		return LevelError
 	case "warn", "warning":
 		return LevelWarn
+	case "notice":
+		return LevelNotice
 	case "info":
 		return LevelInfo
 	case "debug":
 		return LevelDebug
+	case "trace":
+		return LevelTrace
 	}
 
 	return LevelFatal
@@ -62,4 +120,8 @@ const (
 	LevelFatal Level = Level(12)
 	// LevelDebug level. Usually only enabled when debugging. Very verbose logging.
 	LevelDebug Level = Level(slog.LevelDebug)
+	// LevelTrace level. Finer-grained than debug, e.g. for wire dumps.
+	LevelTrace Level = Level(-8)
+	// LevelNotice level. Normal but significant entries, between info and warn.
+	LevelNotice Level = Level(2)
 )
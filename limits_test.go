@@ -0,0 +1,116 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Format: FormatJSON,
+		Output: writer,
+		Limits: &Limits{MaxMessageLength: 10, MaxAttrValueLength: 8, MaxAttrs: 3},
+	})
+
+	decode := func() map[string]any {
+		t.Helper()
+		var m map[string]any
+		if err := json.Unmarshal(writer.Bytes(), &m); err != nil {
+			t.Fatalf("invalid JSON %s: %v", writer.String(), err)
+		}
+		writer.Reset()
+		return m
+	}
+
+	l.With("a", "short").Info("short")
+	if m := decode(); m[KeyTruncated] != nil {
+		t.Errorf("expected no truncation: %+v", m)
+	}
+
+	l.With("body", strings.Repeat("x", 100), "data", map[string]int{"count": 12345}).Info(strings.Repeat("m", 100))
+	m := decode()
+	if m["msg"] != strings.Repeat("m", 10)+TruncationMarker {
+		t.Errorf("expected truncated message, got %v", m["msg"])
+	}
+	if m["body"] != "xxxxxxxx"+TruncationMarker || m["data"] != "map[coun"+TruncationMarker {
+		t.Errorf("expected truncated values, got %v, %v", m["body"], m["data"])
+	}
+	if m[KeyTruncated] != true {
+		t.Errorf("expected truncated field: %+v", m)
+	}
+
+	l.With("a", 1, "b", 2).With("c", 3, "d", 4).Info("many")
+	m = decode()
+	if m["c"] == nil || m["d"] != nil || m[KeyTruncated] != true {
+		t.Errorf("expected the fields after the third to be dropped: %+v", m)
+	}
+}
+
+func TestLimitsRecordBytes(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Output: writer,
+		Limits: &Limits{MaxRecordBytes: 64},
+	})
+
+	l.With("first", "kept").With("blob", strings.Repeat("x", 1000)).Info("message")
+
+	result := writer.String()
+	if !strings.Contains(result, "kept") || strings.Contains(result, "xxx") || !strings.Contains(result, KeyTruncated) {
+		t.Errorf("expected the oversized field to be dropped: %s", result)
+	}
+
+	writer.Reset()
+	l.Info(strings.Repeat("m", 1000))
+	if strings.Count(writer.String(), "m") > 100 || !strings.Contains(writer.String(), TruncationMarker) {
+		t.Errorf("expected the oversized message to be truncated: %s", writer.String())
+	}
+}
+
+func TestLimitsRecordBytesPerFormat(t *testing.T) {
+	kept := map[Format]bool{FormatText: true, FormatJSON: false}
+	for format, keepLast := range kept {
+		writer := &bytes.Buffer{}
+		l := New(LevelInfo, &Option{
+			Format: format,
+			Output: writer,
+			Limits: &Limits{MaxRecordBytes: 40},
+		})
+
+		l.With("a", "xxxx", "b", "xxxx", "c", "xxxx", "d", "yyyy").Info("m")
+
+		result := writer.String()
+		if strings.Contains(result, "yyyy") != keepLast || strings.Contains(result, KeyTruncated) == keepLast {
+			t.Errorf("format %d: expected the last field kept %v by the bytes of the format: %s", format, keepLast, result)
+		}
+	}
+}
+
+type countingFormatter struct {
+	calls *int
+}
+
+func (f countingFormatter) Format(s fmt.State, _ rune) {
+	*f.calls++
+	fmt.Fprint(s, "formatted")
+}
+
+func TestLimitsMessageOnly(t *testing.T) {
+	writer := &bytes.Buffer{}
+	calls := 0
+	l := New(LevelInfo, &Option{Output: writer, Limits: &Limits{MaxMessageLength: 5}})
+
+	l.With("value", countingFormatter{calls: &calls}).Info("a long message")
+
+	if calls != 1 {
+		t.Errorf("expected the value to be formatted only by the output, got %d calls", calls)
+	}
+
+	if !strings.Contains(writer.String(), "a lon"+TruncationMarker) || !strings.Contains(writer.String(), KeyTruncated) {
+		t.Errorf("expected the message to be truncated: %s", writer.String())
+	}
+}
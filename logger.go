@@ -13,41 +13,157 @@ var (
 	bgCtx = context.Background()
 )
 
-type logger slog.Logger
+type logger struct {
+	sl *slog.Logger
+
+	// ctx is passed to the handler with each record.
+	ctx context.Context
+
+	// fn is the value of the KeyFunc field, which AppendFunc appends to.
+	fn string
+
+	// fnParent is sl before the KeyFunc field is added, and fnArgs are the args added after it,
+	// so the KeyFunc field can be replaced rather than duplicated.
+	fnParent *slog.Logger
+	fnArgs   []any
+}
 
 // New creates a new basic logger with the given level and outputs.
 //
 // If option is not provided, the logger will write to the os.Stdout with console format.
 func New(level Level, option ...*Option) Logger {
 	if len(option) != 0 {
-		return (*logger)(slog.New(option[0].createLoggerHandler(level)))
+		return newLogger(option[0].createLoggerHandler(level))
 	}
 
-	return (*logger)(slog.New(defaultOption.createLoggerHandler(level)))
+	return newLogger(defaultOption.createLoggerHandler(level))
+}
+
+func newLogger(h slog.Handler) *logger {
+	return &logger{
+		sl:  slog.New(internal.NewLazyHandler(h)),
+		ctx: bgCtx,
+	}
 }
 
 func (l *logger) Copy() Logger {
-	return (*logger)((*slog.Logger)(l))
+	ll := *l
+	return &ll
 }
 
+// With routes the KeyFunc field to WithFunc, so it's replaced or appended to rather than duplicated.
 func (l *logger) With(args ...any) Logger {
 	if len(args) == 0 {
 		return l
 	}
 
-	return (*logger)((*slog.Logger)(l).With(args...))
+	if function, rest, ok := cutFuncArg(args); ok {
+		ll := l
+		if len(rest) != 0 {
+			ll = l.with(rest...)
+		}
+		return ll.WithFunc(function)
+	}
+
+	return l.with(args...)
+}
+
+// cutFuncArg returns the value of the KeyFunc field of the args of With, and the other args.
+func cutFuncArg(args []any) (string, []any, bool) {
+	for i := 0; i < len(args); i++ {
+		var (
+			value slog.Value
+			n     int
+		)
+
+		switch x := args[i].(type) {
+		case string:
+			if x != KeyFunc || i+1 >= len(args) {
+				i++
+				continue
+			}
+			value, n = slog.AnyValue(args[i+1]), 2
+		case slog.Attr:
+			if x.Key != KeyFunc {
+				continue
+			}
+			value, n = x.Value, 1
+		default:
+			continue
+		}
+
+		rest := make([]any, 0, len(args)-n)
+		rest = append(rest, args[:i]...)
+		rest = append(rest, args[i+n:]...)
+
+		return value.Resolve().String(), rest, true
+	}
+
+	return "", nil, false
+}
+
+func (l *logger) with(args ...any) *logger {
+	ll := &logger{sl: l.sl.With(args...), ctx: l.ctx}
+	if len(l.fn) != 0 {
+		ll.fn = l.fn
+		ll.fnParent = l.fnParent
+		ll.fnArgs = append(l.fnArgs[:len(l.fnArgs):len(l.fnArgs)], args...)
+	}
+
+	return ll
 }
 
 func (l *logger) WithError(err error) Logger {
 	return l.With(KeyErr, err)
 }
 
+// WithCtx also passes the context to the handler with each record, e.g. for Option.Sampling.
 func (l *logger) WithCtx(ctx context.Context) Logger {
-	return l.With(KeyCtx, ctx)
+	ll := l.with(KeyCtx, ctx)
+	ll.ctx = ctx
+	return ll
 }
 
+// bindContext copies the logger and passes the context to the handler with each record.
+func (l *logger) bindContext(ctx context.Context) Logger {
+	ll := *l
+	ll.ctx = ctx
+	return &ll
+}
+
+// WithFunc replaces the KeyFunc field added before, if any.
 func (l *logger) WithFunc(function string) Logger {
-	return l.With(KeyFunc, function)
+	parent, args := l.sl, []any(nil)
+	if len(l.fn) != 0 {
+		parent, args = l.fnParent, l.fnArgs
+	}
+
+	sl := parent.With(KeyFunc, function)
+	if len(args) != 0 {
+		sl = sl.With(args...)
+	}
+
+	return &logger{sl: sl, ctx: l.ctx, fn: function, fnParent: parent, fnArgs: args}
+}
+
+func (l *logger) AppendFunc(function string) Logger {
+	if len(l.fn) == 0 {
+		return l.WithFunc(function)
+	}
+
+	return l.WithFunc(l.fn + FuncSeparator + function)
+}
+
+func (l *logger) AppendCaller() Logger {
+	return l.AppendFunc(callerFunc(1))
+}
+
+func (l *logger) TraceFunc() func() {
+	return traceFunc(l.AppendFunc(callerFunc(1)))
+}
+
+func (l *logger) Start(name string) *Timer {
+	return newTimer(l, name)
 }
 
 func (l *logger) Attach(ctx context.Context) context.Context {
@@ -59,17 +175,17 @@ func (l *logger) Log(level Level, args ...any) {
 
 	switch len(args) {
 	case 0:
-		(*slog.Logger)(l).Log(bgCtx, slogLevel, "")
+		l.sl.Log(l.ctx, slogLevel, "")
 	case 1:
 		if str, ok := args[0].(string); ok {
-			(*slog.Logger)(l).Log(bgCtx, slogLevel, str)
+			l.sl.Log(l.ctx, slogLevel, str)
 		} else {
-			(*slog.Logger)(l).Log(bgCtx, slogLevel, internal.ValueToString(args[0]))
+			l.sl.Log(l.ctx, slogLevel, internal.ValueToString(args[0]))
 		}
 	case 2:
-		(*slog.Logger)(l).Log(bgCtx, slogLevel, fmt.Sprint(args[0], " ", args[1]))
+		l.sl.Log(l.ctx, slogLevel, fmt.Sprint(args[0], " ", args[1]))
 	default:
-		(*slog.Logger)(l).Log(bgCtx, slogLevel, fmt.Sprint(args...))
+		l.sl.Log(l.ctx, slogLevel, fmt.Sprint(args...))
 	}
 }
 
@@ -77,11 +193,19 @@ func (l *logger) Logf(level Level, format string, args ...any) {
 	slogLevel := slog.Level(level)
 
 	if len(args) == 0 {
-		(*slog.Logger)(l).Log(bgCtx, slogLevel, format)
+		l.sl.Log(l.ctx, slogLevel, format)
 		return
 	}
 
-	(*slog.Logger)(l).Log(bgCtx, slogLevel, fmt.Sprintf(format, args...))
+	l.sl.Log(l.ctx, slogLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *logger) Trace(args ...any) {
+	l.Log(LevelTrace, args...)
+}
+
+func (l *logger) Tracef(format string, args ...any) {
+	l.Logf(LevelTrace, format, args...)
 }
 
 func (l *logger) Debug(args ...any) {
@@ -95,26 +219,34 @@ func (l *logger) Debugf(format string, args ...any) {
 func (l *logger) Info(args ...any) {
 	switch len(args) {
 	case 0:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, "")
+		l.sl.Log(l.ctx, slog.LevelInfo, "")
 	case 1:
 		if str, ok := args[0].(string); ok {
-			(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, str)
+			l.sl.Log(l.ctx, slog.LevelInfo, str)
 		} else {
-			(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, fmt.Sprint(args[0]))
+			l.sl.Log(l.ctx, slog.LevelInfo, fmt.Sprint(args[0]))
 		}
 	case 2:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, fmt.Sprint(args[0], " ", args[1]))
+		l.sl.Log(l.ctx, slog.LevelInfo, fmt.Sprint(args[0], " ", args[1]))
 	default:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, fmt.Sprint(args...))
+		l.sl.Log(l.ctx, slog.LevelInfo, fmt.Sprint(args...))
 	}
 }
 
 func (l *logger) Infof(format string, args ...any) {
 	if len(args) == 0 {
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, format)
+		l.sl.Log(l.ctx, slog.LevelInfo, format)
 		return
 	}
-	(*slog.Logger)(l).Log(bgCtx, slog.LevelInfo, fmt.Sprintf(format, args...))
+	l.sl.Log(l.ctx, slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l *logger) Notice(args ...any) {
+	l.Log(LevelNotice, args...)
+}
+
+func (l *logger) Noticef(format string, args ...any) {
+	l.Logf(LevelNotice, format, args...)
 }
 
 func (l *logger) Warn(args ...any) {
@@ -128,26 +260,26 @@ func (l *logger) Warnf(format string, args ...any) {
 func (l *logger) Error(args ...any) {
 	switch len(args) {
 	case 0:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelError, "")
+		l.sl.Log(l.ctx, slog.LevelError, "")
 	case 1:
 		if str, ok := args[0].(string); ok {
-			(*slog.Logger)(l).Log(bgCtx, slog.LevelError, str)
+			l.sl.Log(l.ctx, slog.LevelError, str)
 		} else {
-			(*slog.Logger)(l).Log(bgCtx, slog.LevelError, fmt.Sprint(args[0]))
+			l.sl.Log(l.ctx, slog.LevelError, fmt.Sprint(args[0]))
 		}
 	case 2:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelError, fmt.Sprint(args[0], " ", args[1]))
+		l.sl.Log(l.ctx, slog.LevelError, fmt.Sprint(args[0], " ", args[1]))
 	default:
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelError, fmt.Sprint(args...))
+		l.sl.Log(l.ctx, slog.LevelError, fmt.Sprint(args...))
 	}
 }
 
 func (l *logger) Errorf(format string, args ...any) {
 	if len(args) == 0 {
-		(*slog.Logger)(l).Log(bgCtx, slog.LevelError, format)
+		l.sl.Log(l.ctx, slog.LevelError, format)
 		return
 	}
-	(*slog.Logger)(l).Log(bgCtx, slog.LevelError, fmt.Sprintf(format, args...))
+	l.sl.Log(l.ctx, slog.LevelError, fmt.Sprintf(format, args...))
 }
 
 func (l *logger) Fatal(args ...any) {
@@ -159,3 +291,9 @@ func (l *logger) Fatalf(format string, args ...any) {
 	l.Logf(LevelFatal, format, args...)
 	os.Exit(1)
 }
+
+func (l *logger) RecoverPanic() {
+	if p := recover(); p != nil {
+		logPanic(l, p)
+	}
+}
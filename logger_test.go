@@ -1,12 +1,17 @@
 package logs
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/yanun0323/logs/internal"
 )
 
 func TestVariousLogger(t *testing.T) {
@@ -83,3 +88,173 @@ func TestWithsLoop(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCustomLevels(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelTrace, &Option{Output: writer})
+
+	l.Trace("trace")
+	l.Notice("notice")
+
+	result := writer.String()
+	if !strings.Contains(result, internal.LevelTraceTitle) {
+		t.Errorf("expected trace title in output: %s", result)
+	}
+
+	if !strings.Contains(result, internal.LevelNoticeTitle) {
+		t.Errorf("expected notice title in output: %s", result)
+	}
+
+	writer.Reset()
+	New(LevelInfo, &Option{Output: writer}).Tracef("%s", "trace")
+	if writer.Len() != 0 {
+		t.Errorf("expected trace to be filtered, got: %s", writer.String())
+	}
+
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal} {
+		if got := NewLevel(level.String()); got != level {
+			t.Errorf("NewLevel(%q) = %d, want %d", level.String(), got, level)
+		}
+	}
+
+	writer.Reset()
+	l = New(LevelTrace, &Option{Output: writer, Format: FormatJSON})
+	l.Trace("trace")
+	l.Notice("notice")
+	l.Log(LevelFatal, "fatal")
+
+	result = writer.String()
+	for _, s := range []string{`"level":"TRACE"`, `"level":"NOTICE"`, `"level":"FATAL"`} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected %s in output: %s", s, result)
+		}
+	}
+
+	writer.Reset()
+	l = New(LevelTrace, &Option{Output: writer, Format: FormatText})
+	l.Trace("trace")
+	l.Notice("notice")
+
+	result = writer.String()
+	for _, s := range []string{"level=TRACE", "level=NOTICE"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected %s in output: %s", s, result)
+		}
+	}
+}
+
+type userValuer struct {
+	name string
+}
+
+func (u userValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", u.name))
+}
+
+func TestLevelText(t *testing.T) {
+	type config struct {
+		Level Level
+	}
+
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelFatal, Level(5), Level(-12)} {
+		b, err := json.Marshal(config{Level: level})
+		if err != nil {
+			t.Fatalf("marshal level %d: %v", level, err)
+		}
+
+		var c config
+		if err := json.Unmarshal(b, &c); err != nil {
+			t.Fatalf("unmarshal %s: %v", b, err)
+		}
+
+		if c.Level != level {
+			t.Errorf("expected level %d to round-trip, got %d from %s", level, c.Level, b)
+		}
+	}
+
+	if b, _ := json.Marshal(config{Level: Level(5)}); string(b) != `{"Level":"5"}` {
+		t.Errorf("expected the unnamed level to be encoded as its number, got %s", b)
+	}
+
+	var level Level
+	if err := level.UnmarshalText([]byte("warning")); err != nil || level != LevelWarn {
+		t.Errorf("expected warning to be parsed as warn, got %d, %v", level, err)
+	}
+
+	if err := level.UnmarshalText([]byte("bogus")); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestLazy(t *testing.T) {
+	for _, format := range []Format{FormatConsole, FormatText, FormatJSON, FormatCBOR} {
+		writer := &bytes.Buffer{}
+		calls := 0
+		l := New(LevelInfo, &Option{Output: writer, Format: format}).With("dump", Lazy(func() any {
+			calls++
+			return fmt.Sprintf("value-%d", calls)
+		}))
+
+		l.Debug("filtered")
+		if calls != 0 {
+			t.Fatalf("format %d: expected lazy value not to be computed for filtered message, got %d calls", format, calls)
+		}
+
+		l.With("id", 1).Info("a")
+		l.Info("b")
+		if calls != 2 {
+			t.Errorf("format %d: expected lazy value to be computed for each message, got %d calls", format, calls)
+		}
+
+		if result := writer.String(); !strings.Contains(result, "value-1") || !strings.Contains(result, "value-2") {
+			t.Errorf("format %d: expected the value of each message in output: %q", format, result)
+		}
+	}
+
+	calls := 0
+	lazy := Lazy(func() any {
+		calls++
+		return "shared"
+	})
+
+	ring := NewRingBuffer(2)
+	New(LevelInfo, &Option{Output: &bytes.Buffer{}, Format: FormatJSON, Sinks: []Sink{ring}}).With("dump", lazy).Info("sink")
+	if calls != 1 {
+		t.Errorf("expected lazy value to be computed once for the format and sinks, got %d calls", calls)
+	}
+
+	calls = 0
+	dedup := NewDedupLogger(LevelInfo, 0, &Option{Output: &bytes.Buffer{}, Format: FormatJSON, Sinks: []Sink{ring}}).With("dump", lazy)
+	dedup.Debug("filtered")
+	dedup.Info("dedup")
+	if calls != 1 {
+		t.Errorf("expected lazy value to be computed once by the dedup logger, got %d calls", calls)
+	}
+
+	if records := ring.Query(Query{}); len(records) != 2 || records[1].Attrs["dump"] != "shared" {
+		t.Errorf("expected the lazy value in the sink records: %+v", records)
+	}
+
+	writer := &bytes.Buffer{}
+	New(LevelInfo, &Option{Output: writer}).With("user", userValuer{name: "alice"}).Info("valuer")
+	if !strings.Contains(writer.String(), "alice") || strings.Contains(writer.String(), "userValuer") {
+		t.Errorf("expected resolved value in output: %s", writer.String())
+	}
+}
+
+// testLoggers returns the constructors of all the logger types, writing the messages at or above level.
+func testLoggers(level Level) map[string]func(option *Option) Logger {
+	return map[string]func(option *Option) Logger{
+		"logger": func(option *Option) Logger { return New(level, option) },
+		"ticker": func(option *Option) Logger { return NewTickerLogger(level, 0, option) },
+		"keyed": func(option *Option) Logger {
+			return NewKeyedTickerLogger(level, 0, TickerKeyMessage, 0, option)
+		},
+		"sampling":     func(option *Option) Logger { return NewSamplingLogger(level, time.Hour, 100, 0, option) },
+		"token bucket": func(option *Option) Logger { return NewTokenBucketLogger(level, 1, 100, option) },
+		"dedup":        func(option *Option) Logger { return NewDedupLogger(level, 0, option) },
+		"buffered": func(option *Option) Logger {
+			return NewBufferedLogger(New(level, option), level, 0, 0)
+		},
+	}
+}
@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+// metricsRegistry holds the *internal.Metrics of each logger name.
+var metricsRegistry sync.Map
+
+func loggerMetrics(name string) *internal.Metrics {
+	if m, ok := metricsRegistry.Load(name); ok {
+		return m.(*internal.Metrics)
+	}
+
+	m, _ := metricsRegistry.LoadOrStore(name, &internal.Metrics{})
+	return m.(*internal.Metrics)
+}
+
+// LevelStats are the counters of a level.
+type LevelStats struct {
+	// Emitted is the number of messages written.
+	Emitted uint64
+	// Dropped is the number of messages dropped by the level of the logger.
+	Dropped uint64
+	// WriteErrors is the number of messages failed to be written.
+	WriteErrors uint64
+}
+
+// LoggerStats are the counters of the loggers sharing a name (Option.Name) created with Option.Metrics.
+//
+// Messages of custom levels are counted as the nearest level below them.
+type LoggerStats struct {
+	Name string
+	// BytesWritten is the number of bytes written to the output.
+	BytesWritten uint64
+	Levels       map[Level]LevelStats
+}
+
+// Stats returns the counters of all the loggers created with Option.Metrics, sorted by name.
+func Stats() []LoggerStats {
+	stats := make([]LoggerStats, 0)
+	metricsRegistry.Range(func(key, value any) bool {
+		m := value.(*internal.Metrics)
+		s := LoggerStats{
+			Name:         key.(string),
+			BytesWritten: m.Bytes.Load(),
+			Levels:       make(map[Level]LevelStats, len(internal.MetricsLevels)),
+		}
+
+		for i, level := range internal.MetricsLevels {
+			s.Levels[Level(level)] = LevelStats{
+				Emitted:     m.Emitted[i].Load(),
+				Dropped:     m.Dropped[i].Load(),
+				WriteErrors: m.WriteErrors[i].Load(),
+			}
+		}
+
+		stats = append(stats, s)
+		return true
+	})
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// MetricsHandler returns an http.Handler serving the counters of Stats
+// in the Prometheus text exposition format.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(prometheusText(Stats()))
+	})
+}
+
+func prometheusText(stats []LoggerStats) []byte {
+	buf := &bytes.Buffer{}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(LevelStats) uint64
+	}{
+		{"logs_records_emitted_total", "Number of messages written.", func(s LevelStats) uint64 { return s.Emitted }},
+		{"logs_records_dropped_total", "Number of messages dropped by the level of the logger.", func(s LevelStats) uint64 { return s.Dropped }},
+		{"logs_write_errors_total", "Number of messages failed to be written.", func(s LevelStats) uint64 { return s.WriteErrors }},
+	}
+
+	for _, c := range counters {
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, s := range stats {
+			for _, level := range internal.MetricsLevels {
+				fmt.Fprintf(buf, "%s{logger=\"%s\",level=\"%s\"} %d\n",
+					c.name, escapeLabel(s.Name), Level(level), c.value(s.Levels[Level(level)]))
+			}
+		}
+	}
+
+	buf.WriteString("# HELP logs_bytes_written_total Number of bytes written to the output.\n# TYPE logs_bytes_written_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(buf, "logs_bytes_written_total{logger=\"%s\"} %d\n", escapeLabel(s.Name), s.BytesWritten)
+	}
+
+	return buf.Bytes()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,103 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// findStats returns the stats of the name, or zero stats if there's no logger of the name.
+func findStats(name string) LoggerStats {
+	for _, s := range Stats() {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return LoggerStats{Name: name}
+}
+
+// statsDelta returns the counters increased since the snapshot before.
+func statsDelta(before, after LoggerStats) LoggerStats {
+	delta := LoggerStats{
+		Name:         after.Name,
+		BytesWritten: after.BytesWritten - before.BytesWritten,
+		Levels:       make(map[Level]LevelStats, len(after.Levels)),
+	}
+
+	for level, s := range after.Levels {
+		b := before.Levels[level]
+		delta.Levels[level] = LevelStats{
+			Emitted:     s.Emitted - b.Emitted,
+			Dropped:     s.Dropped - b.Dropped,
+			WriteErrors: s.WriteErrors - b.WriteErrors,
+		}
+	}
+
+	return delta
+}
+
+func TestStats(t *testing.T) {
+	before := findStats("metrics-test")
+
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{Name: "metrics-test", Output: writer, Metrics: true})
+
+	l.Debug("dropped")
+	l.Info("info")
+	l.With("user", "alice").Info("info")
+	l.Error("error")
+
+	New(LevelInfo, &Option{Name: "metrics-test", Output: failingWriter{}, Metrics: true}).Error("failed")
+	New(LevelInfo, &Option{Name: "metrics-test", Output: &bytes.Buffer{}}).Error("not counted")
+
+	s := statsDelta(before, findStats("metrics-test"))
+	if s.BytesWritten != uint64(writer.Len()) {
+		t.Errorf("expected %d bytes, got %d", writer.Len(), s.BytesWritten)
+	}
+
+	expected := map[Level]LevelStats{
+		LevelDebug: {Dropped: 1},
+		LevelInfo:  {Emitted: 2},
+		LevelError: {Emitted: 1, WriteErrors: 1},
+	}
+	for level, e := range expected {
+		if s.Levels[level] != e {
+			t.Errorf("expected %+v of %s, got %+v", e, level, s.Levels[level])
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	New(LevelInfo, &Option{Name: `metrics-"http"`, Output: &bytes.Buffer{}, Metrics: true}).Warn("warn")
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	warn := findStats(`metrics-"http"`).Levels[LevelWarn]
+	if warn.Emitted == 0 {
+		t.Fatalf("expected the warn message to be counted")
+	}
+
+	result := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE logs_records_emitted_total counter",
+		fmt.Sprintf(`logs_records_emitted_total{logger="metrics-\"http\"",level="warn"} %d`, warn.Emitted),
+		`logs_records_dropped_total{logger="metrics-\"http\"",level="warn"} 0`,
+		`logs_bytes_written_total{logger="metrics-\"http\""}`,
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("expected %q in output: %s", line, result)
+		}
+	}
+}
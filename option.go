@@ -4,6 +4,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/yanun0323/logs/internal"
 )
@@ -19,25 +20,171 @@ var defaultOption = &Option{
 // It allows customization of the output format and destination.
 type Option struct {
 	// Format specifies the log output format.
-	// Available formats: FormatConsole (default), FormatText, FormatJSON
+	// Available formats: FormatConsole (default), FormatText, FormatJSON, FormatCBOR
 	Format Format
 
 	// Output specifies the destination writer for log output.
 	// Defaults to os.Stdout if not specified.
 	Output io.Writer
+
+	// Name is the name of the logger, rendered by the {logger} placeholder of Layout.
+	Name string
+
+	// Layout specifies the line layout of FormatConsole, e.g. "{time} {level} [{logger}] {msg} {attrs}".
+	// A placeholder can declare a minimum width: {msg:40} pads the message to 40 columns,
+	// {level:>7} aligns the level to the right. Unknown placeholders are written as is.
+	// Defaults to DefaultLayout if not specified.
+	Layout string
+
+	// AttrStyle specifies how FormatConsole renders attributes.
+	// Defaults to AttrStyleBracket.
+	AttrStyle AttrStyle
+
+	// Color specifies whether FormatConsole writes ANSI colors.
+	// Defaults to ColorModeAuto, which writes colors only when Output is a terminal.
+	Color ColorMode
+
+	// Theme specifies the color palette of FormatConsole.
+	// Defaults to ThemeDefault if not specified.
+	Theme *Theme
+
+	// Sampling keeps or drops the low level messages of a whole trace by its trace ID.
+	// Defaults to writing all messages if not specified.
+	Sampling *TraceSampling
+
+	// Sinks receive every written message as a Record, e.g. a RingBuffer.
+	Sinks []Sink
+
+	// Hooks are fired for the written messages of their levels.
+	Hooks []Hook
+
+	// AsyncHooks fires the hooks in a background goroutine, so slow hooks don't block the logger.
+	// The goroutine runs only while messages are waiting, call FlushHooks to wait for them before
+	// the process exits. The hooks are fired synchronously when too many messages are waiting,
+	// and for the fatal messages after the waiting messages, so they are fired before the process exits.
+	AsyncHooks bool
+
+	// Redaction masks the sensitive data of the fields before they are written,
+	// including the records received by Sinks and Hooks.
+	Redaction *Redaction
+
+	// Limits truncate the oversized messages and fields before they are written.
+	// Defaults to unlimited if not specified.
+	Limits *Limits
+
+	// StackCapture captures the call stack of the messages at or above its level.
+	// Defaults to capturing nothing if not specified.
+	StackCapture *StackCapture
+
+	// Metrics counts the messages and the bytes written by the logger into Stats and MetricsHandler,
+	// shared by the loggers with the same Name. Defaults to false.
+	Metrics bool
+
+	// DurationUnit specifies the unit of the duration fields in FormatJSON, e.g. time.Millisecond
+	// writes 1.5 for 1.5ms. Defaults to integer nanoseconds if not specified.
+	DurationUnit time.Duration
+}
+
+// StackCapture configures the call stack captured with the messages, which is written
+// as the field "error.stack" (KeyErrorsStack): the [error.stack] block in FormatConsole,
+// an array of {function, file, line} in FormatJSON and FormatCBOR, and a frame per line in FormatText.
+//
+// The frames of the logging call, e.g. of logs and log/slog, are skipped.
+type StackCapture struct {
+	// Level is the lowest level to capture, e.g. LevelWarn.
+	// Defaults to LevelError if not specified.
+	Level *Level
+
+	// Depth is the maximum number of the frames.
+	// Defaults to 32 if not specified.
+	Depth int
+
+	// Skip are the prefixes of the functions dropped from the stack, e.g. "net/http.".
+	Skip []string
+}
+
+func (s *StackCapture) level() Level {
+	if s.Level == nil {
+		return LevelError
+	}
+
+	return *s.Level
+}
+
+// Limits are the size limits of the messages, zero is unlimited.
+//
+// The truncated messages and values end with TruncationMarker, and the truncated messages
+// carry the field truncated=true (KeyTruncated).
+type Limits struct {
+	// MaxMessageLength is the maximum bytes of the message.
+	MaxMessageLength int
+
+	// MaxAttrValueLength is the maximum bytes of each field value. The values which aren't
+	// strings are formatted with %+v to be truncated, except errors.
+	MaxAttrValueLength int
+
+	// MaxAttrs is the maximum number of the fields, the exceeding fields are dropped.
+	MaxAttrs int
+
+	// MaxRecordBytes is the maximum bytes of the message and the fields, which are estimated
+	// for each format by the keys, the values and the bytes the format writes around them,
+	// e.g. the quotes of FormatJSON. The fields are kept in order until it's reached.
+	MaxRecordBytes int
 }
 
 // createLoggerHandler creates an appropriate slog.Handler based on the Option configuration.
+func (opt *Option) createLoggerHandler(level Level) slog.Handler {
+	var h slog.Handler
+	if opt.Metrics {
+		metrics := loggerMetrics(opt.Name)
+		h = opt.createFormatHandler(level, internal.NewCountingWriter(opt.output(), metrics))
+		h = internal.NewMetricsHandler(h, metrics)
+	} else {
+		h = opt.createFormatHandler(level, opt.output())
+	}
+
+	sinks := opt.Sinks
+	if len(opt.Hooks) != 0 {
+		sinks = append(sinks[:len(sinks):len(sinks)], newHookSink(opt.Hooks, opt.AsyncHooks))
+	}
+
+	if len(sinks) != 0 {
+		h = internal.NewObserverHandler(h, observe(sinks))
+	}
+
+	if opt.StackCapture != nil {
+		h = internal.NewStackHandler(h, int8(opt.StackCapture.level()), opt.StackCapture.Depth, opt.StackCapture.Skip)
+	}
+
+	if opt.Limits != nil {
+		h = internal.NewLimitHandler(h, internal.Limits(*opt.Limits), opt.Format.fieldOverhead())
+	}
+
+	if opt.Redaction != nil {
+		h = internal.NewReplaceHandler(h, opt.Redaction.redactor().redactAttr)
+	}
+
+	if opt.Sampling != nil {
+		h = internal.NewSamplingHandler(h, int8(opt.Sampling.Level), opt.Sampling.keep())
+	}
+
+	return h
+}
+
+// createFormatHandler creates the slog.Handler writing to w.
 // It returns different handler types based on the specified Format:
 // - FormatText: slog.NewTextHandler
 // - FormatJSON: slog.NewJSONHandler
+// - FormatCBOR: length-prefixed CBOR handler of logs
 // - FormatConsole (default): custom handler of logs
-func (opt *Option) createLoggerHandler(level Level) slog.Handler {
+func (opt *Option) createFormatHandler(level Level, w io.Writer) slog.Handler {
 	switch opt.Format {
 	case FormatText:
-		return slog.NewTextHandler(opt.output(), &slog.HandlerOptions{
+		replaceError := internal.ErrorReplacer(true)
+		return slog.NewTextHandler(w, &slog.HandlerOptions{
 			Level: slog.Level(level),
 			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				a = replaceLevel(groups, a)
 				if a.Key == slog.TimeKey {
 					switch a.Value.Kind() {
 					case slog.KindTime:
@@ -45,16 +192,47 @@ func (opt *Option) createLoggerHandler(level Level) slog.Handler {
 					}
 				}
 
-				return a
+				return replaceError(a)
 			},
 		})
 	case FormatJSON:
-		return slog.NewJSONHandler(opt.output(), &slog.HandlerOptions{
+		replaceError := internal.ErrorReplacer(false)
+		unit := opt.DurationUnit
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{
 			Level: slog.Level(level),
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				a = replaceLevel(groups, a)
+				if unit > time.Nanosecond && a.Value.Kind() == slog.KindDuration {
+					a.Value = slog.Float64Value(float64(a.Value.Duration()) / float64(unit))
+					return a
+				}
+
+				return replaceError(a)
+			},
 		})
+	case FormatCBOR:
+		return internal.NewReplaceHandler(internal.NewCBORHandler(w, int8(level)), internal.ErrorReplacer(false))
 	default:
-		return internal.NewLoggerHandler(opt.output(), int8(level))
+		return internal.NewLoggerHandler(w, int8(level), opt.consoleOption())
+	}
+}
+
+func (opt *Option) consoleOption() *internal.ConsoleOption {
+	o := &internal.ConsoleOption{
+		Name:      opt.Name,
+		AttrStyle: internal.AttrStyle(opt.AttrStyle),
+		NoColor:   !opt.Color.enabled(opt.output()),
+	}
+
+	if len(opt.Layout) != 0 {
+		o.Layout = internal.ParseLayout(opt.Layout)
 	}
+
+	if opt.Theme != nil {
+		o.Theme = opt.Theme.internal()
+	}
+
+	return o
 }
 
 func (opt *Option) output() io.Writer {
@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Record is a structured log message.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Level   Level     `json:"level"`
+	Message string    `json:"msg"`
+
+	// Attrs are the fields of the message, keys of the fields in groups are joined by dots,
+	// e.g. "request.id".
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// Sink receives the messages written by the loggers created with Option.Sinks.
+//
+// Write must be safe for concurrent use. The record is shared by the sinks and must not be modified.
+type Sink interface {
+	Write(r *Record)
+}
+
+// leveledSink is implemented by the sinks which receive only some levels,
+// so the record isn't built for the others.
+type leveledSink interface {
+	enabled(level Level) bool
+}
+
+func newRecord(r slog.Record, attrs []slog.Attr) *Record {
+	rec := &Record{
+		Time:    r.Time,
+		Level:   Level(r.Level),
+		Message: r.Message,
+	}
+
+	if len(attrs) != 0 {
+		rec.Attrs = make(map[string]any, len(attrs))
+		for _, a := range attrs {
+			rec.addAttr("", a)
+		}
+	}
+
+	return rec
+}
+
+func (rec *Record) addAttr(prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if len(a.Key) == 0 && v.Kind() != slog.KindGroup {
+		return
+	}
+
+	key := a.Key
+	if len(prefix) != 0 && len(key) != 0 {
+		key = prefix + "." + key
+	} else if len(key) == 0 {
+		key = prefix
+	}
+
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			rec.addAttr(key, ga)
+		}
+		return
+	}
+
+	rec.Attrs[key] = recordValue(v)
+}
+
+// recordValue returns the value kept by the record, which is meant to be encoded as JSON.
+func recordValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case nil:
+			return nil
+		case error:
+			return x.Error()
+		case context.Context:
+			return fmt.Sprint(x)
+		case json.Marshaler, encoding.TextMarshaler:
+			return x
+		case []byte:
+			return string(x)
+		default:
+			if _, err := json.Marshal(x); err != nil {
+				return fmt.Sprintf("%+v", x)
+			}
+			return x
+		}
+	case slog.KindDuration:
+		return v.Duration().String()
+	default:
+		return v.Any()
+	}
+}
+
+// observe returns a function writing the records to the sinks.
+func observe(sinks []Sink) func(ctx context.Context, r slog.Record, attrs []slog.Attr) {
+	return func(_ context.Context, r slog.Record, attrs []slog.Attr) {
+		var rec *Record
+		for _, s := range sinks {
+			if ls, ok := s.(leveledSink); ok && !ls.enabled(Level(r.Level)) {
+				continue
+			}
+
+			if rec == nil {
+				rec = newRecord(r, attrs)
+			}
+			s.Write(rec)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+// DefaultRecoverMessage is the message of the recovered panics when RecoverOption.Message is not specified.
+const DefaultRecoverMessage = "panic recovered"
+
+// RecoverOption represents the configuration of Recover and Go.
+type RecoverOption struct {
+	// Message is the message of the recovered panic.
+	// Defaults to DefaultRecoverMessage if not specified.
+	Message string
+
+	// Fatal logs the panic at the fatal level, which exits the program, instead of the error level.
+	Fatal bool
+
+	// Repanic panics again with the recovered value after logging it.
+	// It's ignored when Fatal is set.
+	Repanic bool
+}
+
+// Recover recovers a panic and logs it with the panic value as the error (KeyErr) and the
+// stack of the panic (KeyErrorsStack). It does nothing when there's no panic.
+//
+// It must be called directly by defer:
+//
+//	defer logs.Recover(logger)
+func Recover(logger Logger, option ...*RecoverOption) {
+	if p := recover(); p != nil {
+		logPanic(logger, p, option...)
+	}
+}
+
+// Go runs fn in a new goroutine, whose panic is recovered and logged by the logger of the context (Get).
+func Go(ctx context.Context, fn func(ctx context.Context), option ...*RecoverOption) {
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				logPanic(Get(ctx), p, option...)
+			}
+		}()
+
+		fn(ctx)
+	}()
+}
+
+// logPanic logs the recovered value, it must be called by the deferred function
+// so that the stack of the panic can be captured.
+func logPanic(logger Logger, p any, option ...*RecoverOption) {
+	opt := &RecoverOption{}
+	if len(option) != 0 && option[0] != nil {
+		opt = option[0]
+	}
+
+	msg := opt.Message
+	if len(msg) == 0 {
+		msg = DefaultRecoverMessage
+	}
+
+	err, ok := p.(error)
+	if !ok {
+		err = fmt.Errorf("%v", p)
+	}
+
+	l := logger.WithError(err).With(KeyErrorsStack, internal.CaptureStack(internal.DefaultStackDepth, nil))
+	if opt.Fatal {
+		l.Fatal(msg)
+		return
+	}
+
+	l.Error(msg)
+
+	if opt.Repanic {
+		panic(p)
+	}
+}
@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func panicWith(l Logger, v any) {
+	defer l.RecoverPanic()
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{Output: writer, Color: ColorModeNever})
+
+	func() {
+		defer Recover(l)
+		panic("boom")
+	}()
+
+	result := writer.String()
+	for _, s := range []string{"ERROR", DefaultRecoverMessage, "[error] boom", "[" + KeyErrorsStack + "]", "TestRecover"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected %q in output: %s", s, result)
+		}
+	}
+
+	writer.Reset()
+	func() {
+		defer func() {
+			if p := recover(); p == nil {
+				t.Error("expected the panic to be raised again")
+			}
+		}()
+		defer Recover(l, &RecoverOption{Message: "worker crashed", Repanic: true})
+		panic(errors.New("fatal error"))
+	}()
+
+	if !strings.Contains(writer.String(), "worker crashed") || !strings.Contains(writer.String(), "fatal error") {
+		t.Errorf("expected the panic to be logged: %s", writer.String())
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	for name, newLogger := range testLoggers(LevelInfo) {
+		writer := &bytes.Buffer{}
+		panicWith(newLogger(&Option{Output: writer, Color: ColorModeNever}), name+" panic")
+
+		if !strings.Contains(writer.String(), name+" panic") {
+			t.Errorf("%s: expected the panic to be logged: %s", name, writer.String())
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	writer := &syncBuffer{}
+	ctx := New(LevelInfo, &Option{Output: writer}).Attach(context.Background())
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	Go(ctx, func(ctx context.Context) {
+		defer wg.Done()
+		panic("goroutine panic")
+	})
+	wg.Wait()
+
+	for i := 0; i < 100 && !strings.Contains(writer.String(), "goroutine panic"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	if !strings.Contains(writer.String(), "goroutine panic") {
+		t.Errorf("expected the panic to be logged: %s", writer.String())
+	}
+}
+
+func TestRecoverStackCapture(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Output:       writer,
+		Format:       FormatJSON,
+		StackCapture: &StackCapture{},
+	})
+
+	panicWith(l, "boom")
+
+	if n := strings.Count(writer.String(), `"`+KeyErrorsStack+`"`); n != 1 {
+		t.Errorf("expected a single stack, got %d: %s", n, writer.String())
+	}
+
+	writer.Reset()
+	l.With(KeyErrorsStack, "given").Error("given stack")
+	l.Error("captured stack")
+
+	lines := strings.Split(strings.TrimSpace(writer.String()), "\n")
+	if len(lines) != 2 || strings.Count(lines[0], KeyErrorsStack) != 1 || strings.Count(lines[1], KeyErrorsStack) != 1 {
+		t.Errorf("expected a single stack per message: %s", writer.String())
+	}
+}
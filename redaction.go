@@ -0,0 +1,275 @@
+package logs
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactionMask replaces the redacted values when Redaction.Mask is not specified.
+const DefaultRedactionMask = "[REDACTED]"
+
+// DefaultRedactionKeys are the common keys of sensitive data, for Redaction.Keys.
+var DefaultRedactionKeys = []string{
+	"password", "passwd", "secret", "*_secret",
+	"authorization", "cookie", "token", "*_token", "api_key", "apikey",
+}
+
+// Common patterns of sensitive data, for Redaction.Patterns.
+var (
+	PatternEmail      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	PatternCreditCard = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	PatternJWT        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// Redactable is implemented by the values which hide their sensitive data in logs.
+//
+// The loggers created with Option.Redaction log the value returned by Redacted instead.
+type Redactable interface {
+	Redacted() any
+}
+
+// Redaction masks the sensitive data of the fields in all formats, including the values
+// in the maps, slices, pointers and exported struct fields of the fields.
+type Redaction struct {
+	// Keys are the glob patterns of the keys whose values are masked, matched case-insensitively,
+	// e.g. "password" or "*_token". Keys of the fields in groups and of the maps are matched too.
+	Keys []string
+
+	// Patterns are the regular expressions of the sensitive data in the string values,
+	// the matched parts are masked, e.g. PatternEmail.
+	Patterns []*regexp.Regexp
+
+	// Mask replaces the masked data.
+	// Defaults to DefaultRedactionMask if not specified.
+	Mask string
+}
+
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+func (rd *Redaction) redactor() *redactor {
+	r := &redactor{
+		keys:     make([]string, 0, len(rd.Keys)),
+		patterns: rd.Patterns,
+		mask:     rd.Mask,
+	}
+
+	for _, key := range rd.Keys {
+		r.keys = append(r.keys, strings.ToLower(key))
+	}
+
+	if len(r.mask) == 0 {
+		r.mask = DefaultRedactionMask
+	}
+
+	return r
+}
+
+func (r *redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *redactor) maskString(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllLiteralString(s, r.mask)
+	}
+
+	return s
+}
+
+func (r *redactor) redactAttr(a slog.Attr) slog.Attr {
+	if r.matchKey(a.Key) {
+		return slog.String(a.Key, r.mask)
+	}
+
+	if a.Value.Kind() == slog.KindLogValuer {
+		// keep the value lazy, it's redacted when it's resolved
+		a.Value = slog.AnyValue(redactedValuer{redactor: r, valuer: a.Value.LogValuer()})
+		return a
+	}
+
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(r.maskString(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = r.redactAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	case slog.KindAny:
+		a.Value = slog.AnyValue(r.redactValue(v.Any()))
+	default:
+		a.Value = v
+	}
+
+	return a
+}
+
+type redactedValuer struct {
+	redactor *redactor
+	valuer   slog.LogValuer
+}
+
+func (v redactedValuer) LogValue() slog.Value {
+	return v.redactor.redactAttr(slog.Attr{Value: v.valuer.LogValue()}).Value
+}
+
+// _maxRedactionDepth is the depth of the nested values walked by the redactor,
+// the deeper values, e.g. of cyclic pointers, are masked.
+const _maxRedactionDepth = 16
+
+func (r *redactor) redactValue(x any) any {
+	return r.redactNested(x, 0)
+}
+
+func (r *redactor) redactNested(x any, depth int) any {
+	if depth > _maxRedactionDepth {
+		return r.mask
+	}
+
+	switch v := x.(type) {
+	case nil:
+		return nil
+	case Redactable:
+		return v.Redacted()
+	case string:
+		return r.maskString(v)
+	case []byte:
+		return v
+	case error:
+		// keep the error unless its message is masked, so the stack can still be extracted
+		if msg := v.Error(); r.maskString(msg) != msg {
+			return r.maskString(msg)
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return x
+		}
+
+		m := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if r.matchKey(key) {
+				m[key] = r.mask
+			} else {
+				m[key] = r.redactNested(iter.Value().Interface(), depth+1)
+			}
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if !mayBeSensitive(rv.Type().Elem()) {
+			return x
+		}
+
+		s := make([]any, rv.Len())
+		for i := range s {
+			s[i] = r.redactNested(rv.Index(i).Interface(), depth+1)
+		}
+		return s
+	case reflect.Pointer:
+		if rv.IsNil() || formatsItself(x) || !mayBeSensitive(rv.Type().Elem()) {
+			return x
+		}
+
+		return r.redactNested(rv.Elem().Interface(), depth+1)
+	case reflect.Struct:
+		return r.redactStruct(x, rv, depth)
+	default:
+		return x
+	}
+}
+
+// redactStruct converts the exported fields of the struct into a map keyed by their json names,
+// the fields are masked when their names or json names match the keys. The structs formatting
+// themselves, e.g. time.Time, are kept as is.
+func (r *redactor) redactStruct(x any, rv reflect.Value, depth int) any {
+	if formatsItself(x) {
+		return x
+	}
+
+	t := rv.Type()
+	sensitive := false
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() && mayBeSensitive(f.Type) {
+			sensitive = true
+			break
+		}
+	}
+
+	if !sensitive {
+		return x
+	}
+
+	m := make(map[string]any, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		key := f.Name
+		if len(name) != 0 {
+			key = name
+		}
+
+		if r.matchKey(f.Name) || r.matchKey(key) {
+			m[key] = r.mask
+		} else {
+			m[key] = r.redactNested(rv.Field(i).Interface(), depth+1)
+		}
+	}
+
+	return m
+}
+
+// formatsItself reports whether the value is formatted by its own methods, e.g. *url.URL, so it's
+// kept as is rather than converted into a map of its fields.
+func formatsItself(x any) bool {
+	switch x.(type) {
+	case fmt.Stringer, encoding.TextMarshaler, json.Marshaler, slog.LogValuer, error, context.Context:
+		return true
+	default:
+		return false
+	}
+}
+
+// mayBeSensitive reports whether the values of the type may contain strings.
+func mayBeSensitive(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Interface, reflect.Map, reflect.Slice, reflect.Array, reflect.Pointer, reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,155 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type secretString string
+
+func (secretString) Redacted() any {
+	return "***"
+}
+
+func TestRedaction(t *testing.T) {
+	redaction := &Redaction{
+		Keys:     DefaultRedactionKeys,
+		Patterns: []*regexp.Regexp{PatternEmail, PatternCreditCard, PatternJWT},
+	}
+
+	for _, format := range []Format{FormatConsole, FormatText, FormatJSON} {
+		writer := &bytes.Buffer{}
+		l := New(LevelInfo, &Option{Format: format, Output: writer, Redaction: redaction})
+
+		l.With(
+			"password", "hunter2",
+			"access_token", "abc123",
+			"payload", map[string]any{
+				"Authorization": "Bearer xyz",
+				"card":          "4111 1111 1111 1111",
+				"nested":        map[string]string{"api_key": "k-42"},
+			},
+			"user", secretString("alice"),
+		).Info("request")
+		l.With("contact", "mail alice@example.com", "session", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln").Info("login")
+
+		result := writer.String()
+		for _, leaked := range []string{"hunter2", "abc123", "xyz", "4111", "k-42", "alice", "example.com", "eyJ"} {
+			if strings.Contains(result, leaked) {
+				t.Errorf("format %d: expected %q to be redacted: %s", format, leaked, result)
+			}
+		}
+
+		if !strings.Contains(result, DefaultRedactionMask) || !strings.Contains(result, "***") || !strings.Contains(result, "mail") {
+			t.Errorf("format %d: expected masked values: %s", format, result)
+		}
+	}
+}
+
+func TestRedactionLazy(t *testing.T) {
+	writer := &bytes.Buffer{}
+	calls := 0
+	l := New(LevelInfo, &Option{
+		Output:    writer,
+		Redaction: &Redaction{Patterns: []*regexp.Regexp{PatternEmail}},
+	}).With("contact", Lazy(func() any {
+		calls++
+		return "alice@example.com"
+	}))
+
+	l.Debug("filtered")
+	if calls != 0 {
+		t.Fatalf("expected lazy value not to be computed, got %d calls", calls)
+	}
+
+	l.Info("written")
+	if calls != 1 || strings.Contains(writer.String(), "alice") || !strings.Contains(writer.String(), DefaultRedactionMask) {
+		t.Errorf("expected lazy value to be redacted once computed (%d calls): %s", calls, writer.String())
+	}
+}
+
+type redactionUser struct {
+	Name     string
+	Password string
+	Token    string `json:"access_token"`
+	Profile  *redactionProfile
+	internal string
+}
+
+type redactionProfile struct {
+	Email string `json:"email"`
+	Age   int
+}
+
+func TestRedactionStructs(t *testing.T) {
+	redaction := &Redaction{
+		Keys:     DefaultRedactionKeys,
+		Patterns: []*regexp.Regexp{PatternEmail},
+	}
+
+	for _, format := range []Format{FormatConsole, FormatText, FormatJSON} {
+		writer := &bytes.Buffer{}
+		l := New(LevelInfo, &Option{Format: format, Output: writer, Redaction: redaction})
+
+		user := redactionUser{
+			Name:     "alice",
+			Password: "hunter2",
+			Token:    "abc123",
+			Profile:  &redactionProfile{Email: "alice@example.com", Age: 30},
+		}
+		payload := map[string]any{"secret": "s-42"}
+
+		l.With(
+			"struct", struct{ Password string }{"p-1"},
+			"user", user,
+			"pointer", &user,
+			"map_pointer", &payload,
+			"users", []redactionUser{user},
+			"when", time.Unix(0, 0),
+		).Info("request")
+
+		result := writer.String()
+		for _, leaked := range []string{"p-1", "hunter2", "abc123", "example.com", "s-42"} {
+			if strings.Contains(result, leaked) {
+				t.Errorf("format %d: expected %q to be redacted: %s", format, leaked, result)
+			}
+		}
+
+		for _, kept := range []string{"alice", "30", "1970"} {
+			if !strings.Contains(result, kept) {
+				t.Errorf("format %d: expected %q to be kept: %s", format, kept, result)
+			}
+		}
+	}
+}
+
+func TestRedactionKeepsFormattedPointers(t *testing.T) {
+	u, _ := url.Parse("https://example.com/a?b=c")
+	ctx := context.WithValue(context.Background(), redactionCtxKey{}, "v")
+	timestamp := regexp.MustCompile(`\d{4}[-/]\d{2}[-/]\d{2}[T ][\d:.]+Z?`)
+
+	for _, format := range []Format{FormatConsole, FormatText, FormatJSON} {
+		var results [2]string
+		for i, redaction := range []*Redaction{nil, {Keys: DefaultRedactionKeys}} {
+			writer := &bytes.Buffer{}
+			l := New(LevelInfo, &Option{Format: format, Output: writer, Redaction: redaction})
+			l.With("url", u).WithCtx(ctx).Info("request")
+			results[i] = timestamp.ReplaceAllString(writer.String(), "")
+		}
+
+		if results[0] != results[1] {
+			t.Errorf("format %d: expected the pointers to be kept:\n%s\n%s", format, results[0], results[1])
+		}
+
+		if format != FormatJSON && !strings.Contains(results[1], "https://example.com/a?b=c") {
+			t.Errorf("format %d: expected the url to be kept: %s", format, results[1])
+		}
+	}
+}
+
+type redactionCtxKey struct{}
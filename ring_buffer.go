@@ -0,0 +1,191 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RingBuffer is a Sink which keeps the latest records in memory.
+//
+// It's also an http.Handler serving the records matched by the query parameters as JSON:
+//
+//	level    the minimum level, e.g. "warn"
+//	since    the earliest time in RFC 3339, inclusive
+//	until    the latest time in RFC 3339, exclusive
+//	contains the substring of the message
+//	attr     the field equality as key=value, can be repeated
+//	limit    the maximum number of the latest records
+type RingBuffer struct {
+	mu      sync.RWMutex
+	records []*Record
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a new ring buffer keeping at most capacity records.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{
+		records: make([]*Record, max(capacity, 1)),
+	}
+}
+
+// Query filters the records of a ring buffer, the zero value matches all records.
+type Query struct {
+	// MinLevel is the minimum level of the records, nil matches all levels.
+	MinLevel *Level
+
+	// Since is the earliest time of the records, inclusive. Zero is unbounded.
+	Since time.Time
+
+	// Until is the latest time of the records, exclusive. Zero is unbounded.
+	Until time.Time
+
+	// Contains is the substring of the message.
+	Contains string
+
+	// Attrs are the fields the records must have, compared by the formatted value.
+	Attrs map[string]string
+
+	// Limit is the maximum number of the latest records. Zero is unlimited.
+	Limit int
+}
+
+func (q *Query) match(r *Record) bool {
+	if q.MinLevel != nil && r.Level < *q.MinLevel {
+		return false
+	}
+
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false
+	}
+
+	if len(q.Contains) != 0 && !strings.Contains(r.Message, q.Contains) {
+		return false
+	}
+
+	for key, expected := range q.Attrs {
+		v, ok := r.Attrs[key]
+		if !ok || fmt.Sprint(v) != expected {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *RingBuffer) Write(r *Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records[b.next] = r
+	b.next++
+	if b.next == len(b.records) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// Len returns the number of the records in the ring buffer.
+func (b *RingBuffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.full {
+		return len(b.records)
+	}
+	return b.next
+}
+
+// Query returns the records matched by the query, from the oldest to the latest.
+func (b *RingBuffer) Query(q Query) []*Record {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	n := b.next
+	if b.full {
+		n = len(b.records)
+	}
+
+	// walk from the latest record, so the limit keeps the latest ones
+	result := make([]*Record, 0)
+	for i := 0; i < n && (q.Limit <= 0 || len(result) < q.Limit); i++ {
+		r := b.records[(b.next-1-i+len(b.records))%len(b.records)]
+		if q.match(r) {
+			result = append(result, r)
+		}
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+func (b *RingBuffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(b.Query(q)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseQuery(r *http.Request) (Query, error) {
+	var q Query
+	values := r.URL.Query()
+
+	if s := values.Get("level"); len(s) != 0 {
+		var level Level
+		if err := level.UnmarshalText([]byte(s)); err != nil {
+			return q, err
+		}
+		q.MinLevel = &level
+	}
+
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if s := values.Get(name); len(s) != 0 {
+			v, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*t = v
+		}
+	}
+
+	q.Contains = values.Get("contains")
+
+	for _, attr := range values["attr"] {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			return q, fmt.Errorf("invalid attr: %q, expected key=value", attr)
+		}
+		if q.Attrs == nil {
+			q.Attrs = make(map[string]string)
+		}
+		q.Attrs[key] = value
+	}
+
+	if s := values.Get("limit"); len(s) != 0 {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return q, fmt.Errorf("invalid limit: %w", err)
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
@@ -0,0 +1,83 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer(t *testing.T) {
+	ring := NewRingBuffer(3)
+	l := New(LevelDebug, &Option{Output: io.Discard, Sinks: []Sink{ring}})
+
+	l.Debug("dropped by capacity")
+	l.With("user", "alice").Info("login")
+	l.WithError(errors.New("timeout")).Warn("retry")
+	l.With("user", "bob", "attempt", 2).Error("login failed")
+
+	if ring.Len() != 3 {
+		t.Fatalf("expected 3 records, got %d", ring.Len())
+	}
+
+	all := ring.Query(Query{})
+	if len(all) != 3 || all[0].Message != "login" || all[2].Message != "login failed" {
+		t.Fatalf("unexpected records: %+v", all)
+	}
+
+	if all[1].Attrs[KeyErr] != "timeout" {
+		t.Errorf("expected error attr, got: %+v", all[1].Attrs)
+	}
+
+	warn := LevelWarn
+	if result := ring.Query(Query{MinLevel: &warn}); len(result) != 2 {
+		t.Errorf("expected 2 records at or above warn, got %d", len(result))
+	}
+
+	if result := ring.Query(Query{Contains: "login"}); len(result) != 2 {
+		t.Errorf("expected 2 records containing login, got %d", len(result))
+	}
+
+	if result := ring.Query(Query{Attrs: map[string]string{"user": "bob", "attempt": "2"}}); len(result) != 1 || result[0].Message != "login failed" {
+		t.Errorf("expected the record of bob, got %+v", result)
+	}
+
+	if result := ring.Query(Query{Limit: 1}); len(result) != 1 || result[0].Message != "login failed" {
+		t.Errorf("expected the latest record, got %+v", result)
+	}
+
+	if result := ring.Query(Query{Since: time.Now().Add(time.Minute)}); len(result) != 0 {
+		t.Errorf("expected no record in the future, got %+v", result)
+	}
+}
+
+func TestRingBufferHTTP(t *testing.T) {
+	ring := NewRingBuffer(10)
+	l := New(LevelDebug, &Option{Output: io.Discard, Sinks: []Sink{ring}})
+	l.With("user", "alice").Info("login")
+	l.With("user", "bob").Warn("login")
+
+	rec := httptest.NewRecorder()
+	ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?level=info&attr=user%3Dbob", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1 || result[0]["level"] != "warn" || result[0]["msg"] != "login" {
+		t.Errorf("unexpected response: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?level=unknown", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", rec.Code)
+	}
+}
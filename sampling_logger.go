@@ -0,0 +1,315 @@
+package logs
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// _samplingCounters is the number of counters of a sampling logger,
+// keys sharing the same counter are sampled together.
+const _samplingCounters = 4096
+
+// sampler decides whether a message should be logged.
+type sampler interface {
+	allow(level Level, key string) bool
+}
+
+type sampledLogger struct {
+	sampler sampler
+
+	Logger
+}
+
+// NewSamplingLogger creates a new sampling logger with the given level.
+//
+// In every tick, a sampling logger logs the first 'first' messages of each level and message
+// template, then every 'thereafter'th message, the others are dropped. Zero 'thereafter' drops all
+// messages after the first ones. The message template is the format of the 'f' methods, or the
+// first argument of the others when it's a string.
+//
+// Fatal messages are never dropped.
+//
+// If option is not provided, the logger will write to the os.Stdout with console format.
+func NewSamplingLogger(level Level, tick time.Duration, first, thereafter int, option ...*Option) Logger {
+	return &sampledLogger{
+		sampler: &countSampler{
+			tick:       tick.Nanoseconds(),
+			first:      uint64(max(first, 0)),
+			thereafter: uint64(max(thereafter, 0)),
+		},
+		Logger: New(level, option...),
+	}
+}
+
+// NewTokenBucketLogger creates a new token bucket logger with the given level.
+//
+// A token bucket logger logs at most 'burst' messages at once, and refills 'rate' messages
+// per second, the others are dropped. A non-positive rate drops all messages.
+//
+// Fatal messages are never dropped.
+//
+// If option is not provided, the logger will write to the os.Stdout with console format.
+func NewTokenBucketLogger(level Level, rate float64, burst int, option ...*Option) Logger {
+	var interval, tolerance int64
+	if rate > 0 {
+		interval = max(int64(float64(time.Second)/rate), 1)
+		tolerance = math.MaxInt64
+		if n := int64(max(burst-1, 0)); n <= math.MaxInt64/interval {
+			tolerance = interval * n
+		}
+	}
+
+	return &sampledLogger{
+		sampler: &bucketSampler{
+			interval:  interval,
+			tolerance: tolerance,
+		},
+		Logger: New(level, option...),
+	}
+}
+
+// sampleKey returns the message template of the logging methods.
+func sampleKey(template string, args []any) string {
+	if len(template) != 0 || len(args) == 0 {
+		return template
+	}
+
+	if str, ok := args[0].(string); ok {
+		return str
+	}
+
+	return ""
+}
+
+func (l *sampledLogger) Copy() Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: l.Logger.Copy()}
+}
+
+func (l *sampledLogger) WithError(err error) Logger {
+	return l.With(KeyErr, err)
+}
+
+func (l *sampledLogger) WithFunc(function string) Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: l.Logger.WithFunc(function)}
+}
+
+func (l *sampledLogger) AppendFunc(function string) Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: l.Logger.AppendFunc(function)}
+}
+
+func (l *sampledLogger) AppendCaller() Logger {
+	return l.AppendFunc(callerFunc(1))
+}
+
+func (l *sampledLogger) WithCtx(ctx context.Context) Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: l.Logger.WithCtx(ctx)}
+}
+
+func (l *sampledLogger) With(args ...any) Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: l.Logger.With(args...)}
+}
+
+func (l *sampledLogger) Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logAttachKey, l)
+}
+
+func (l *sampledLogger) bindContext(ctx context.Context) Logger {
+	return &sampledLogger{sampler: l.sampler, Logger: bindContext(l.Logger, ctx)}
+}
+
+func (l *sampledLogger) Log(level Level, args ...any) {
+	if level >= LevelFatal || l.sampler.allow(level, sampleKey("", args)) {
+		l.Logger.Log(level, args...)
+	}
+}
+
+func (l *sampledLogger) Logf(level Level, format string, args ...any) {
+	if level >= LevelFatal || l.sampler.allow(level, format) {
+		l.Logger.Logf(level, format, args...)
+	}
+}
+
+func (l *sampledLogger) Trace(args ...any) {
+	if l.sampler.allow(LevelTrace, sampleKey("", args)) {
+		l.Logger.Trace(args...)
+	}
+}
+
+func (l *sampledLogger) Tracef(format string, args ...any) {
+	if l.sampler.allow(LevelTrace, format) {
+		l.Logger.Tracef(format, args...)
+	}
+}
+
+func (l *sampledLogger) Debug(args ...any) {
+	if l.sampler.allow(LevelDebug, sampleKey("", args)) {
+		l.Logger.Debug(args...)
+	}
+}
+
+func (l *sampledLogger) Debugf(format string, args ...any) {
+	if l.sampler.allow(LevelDebug, format) {
+		l.Logger.Debugf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Info(args ...any) {
+	if l.sampler.allow(LevelInfo, sampleKey("", args)) {
+		l.Logger.Info(args...)
+	}
+}
+
+func (l *sampledLogger) Infof(format string, args ...any) {
+	if l.sampler.allow(LevelInfo, format) {
+		l.Logger.Infof(format, args...)
+	}
+}
+
+func (l *sampledLogger) Notice(args ...any) {
+	if l.sampler.allow(LevelNotice, sampleKey("", args)) {
+		l.Logger.Notice(args...)
+	}
+}
+
+func (l *sampledLogger) Noticef(format string, args ...any) {
+	if l.sampler.allow(LevelNotice, format) {
+		l.Logger.Noticef(format, args...)
+	}
+}
+
+func (l *sampledLogger) Warn(args ...any) {
+	if l.sampler.allow(LevelWarn, sampleKey("", args)) {
+		l.Logger.Warn(args...)
+	}
+}
+
+func (l *sampledLogger) Warnf(format string, args ...any) {
+	if l.sampler.allow(LevelWarn, format) {
+		l.Logger.Warnf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Error(args ...any) {
+	if l.sampler.allow(LevelError, sampleKey("", args)) {
+		l.Logger.Error(args...)
+	}
+}
+
+func (l *sampledLogger) Errorf(format string, args ...any) {
+	if l.sampler.allow(LevelError, format) {
+		l.Logger.Errorf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Fatal(args ...any) {
+	l.Logger.Fatal(args...)
+}
+
+func (l *sampledLogger) Fatalf(format string, args ...any) {
+	l.Logger.Fatalf(format, args...)
+}
+
+func (l *sampledLogger) RecoverPanic() {
+	if p := recover(); p != nil {
+		logPanic(l.Logger, p)
+	}
+}
+
+func (l *sampledLogger) TraceFunc() func() {
+	return traceFunc(l.AppendFunc(callerFunc(1)))
+}
+
+func (l *sampledLogger) Start(name string) *Timer {
+	return newTimer(l, name)
+}
+
+// countSampler logs the first messages of each key in a tick, then every nth message.
+type countSampler struct {
+	tick       int64
+	first      uint64
+	thereafter uint64
+	counters   [_samplingCounters]samplingCounter
+}
+
+func (s *countSampler) allow(level Level, key string) bool {
+	n := s.counters[samplingIndex(level, key)].incCheckReset(time.Now().UnixNano(), s.tick)
+	if n <= s.first {
+		return true
+	}
+
+	return s.thereafter != 0 && (n-s.first)%s.thereafter == 0
+}
+
+// samplingIndex hashes the level and the key into the index of the counters with FNV-1a.
+func samplingIndex(level Level, key string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+
+	h := uint32(offset32)
+	h = (h ^ uint32(uint8(level))) * prime32
+	for i := 0; i < len(key); i++ {
+		h = (h ^ uint32(key[i])) * prime32
+	}
+
+	return h % _samplingCounters
+}
+
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// incCheckReset increases the counter, and resets it when the tick has passed.
+func (c *samplingCounter) incCheckReset(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		// another goroutine has reset the counter
+		return c.count.Add(1)
+	}
+
+	return 1
+}
+
+// bucketSampler is a token bucket implemented as GCRA (generic cell rate algorithm),
+// so the state can be updated with a single atomic value.
+type bucketSampler struct {
+	// interval is the nanoseconds to refill a token, zero drops all messages.
+	interval int64
+	// tolerance is the nanoseconds the theoretical arrival time can be ahead of now, which makes the burst.
+	tolerance int64
+	// tat is the theoretical arrival time of the next message in unix nanoseconds.
+	tat atomic.Int64
+}
+
+func (s *bucketSampler) allow(Level, string) bool {
+	if s.interval == 0 {
+		return false
+	}
+
+	now := time.Now().UnixNano()
+	for {
+		tat := s.tat.Load()
+		next := max(tat, now)
+		if next-now > s.tolerance {
+			return false
+		}
+
+		newTat := next + s.interval
+		if newTat < next {
+			newTat = math.MaxInt64
+		}
+
+		if s.tat.CompareAndSwap(tat, newTat) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewSamplingLogger(LevelDebug, time.Hour, 2, 3, &Option{Output: writer})
+
+	for i := 0; i < 11; i++ {
+		l.Infof("sampled %d", i)
+		l.Warn("other")
+	}
+
+	result := writer.String()
+	// 1, 2, 5, 8, 11
+	if got := strings.Count(result, "sampled"); got != 5 {
+		t.Errorf("Expected 5 sampled lines, but got %d: %s", got, result)
+	}
+
+	if got := strings.Count(result, "other"); got != 5 {
+		t.Errorf("Expected 5 other lines, but got %d: %s", got, result)
+	}
+}
+
+func TestTokenBucketLogger(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := NewTokenBucketLogger(LevelDebug, 20, 3, &Option{Output: writer})
+
+	for i := 0; i < 10; i++ {
+		l.Info("burst")
+	}
+
+	if got := strings.Count(writer.String(), "\n"); got != 3 {
+		t.Errorf("Expected 3 lines, but got %d: %s", got, writer.String())
+	}
+
+	writer.Reset()
+	time.Sleep(60 * time.Millisecond)
+	l.Info("refilled")
+
+	if writer.Len() == 0 {
+		t.Error("Expected output after refill, but got none")
+	}
+
+	writer.Reset()
+	NewTokenBucketLogger(LevelDebug, 0, 10, &Option{Output: writer}).Error("dropped")
+	if writer.Len() != 0 {
+		t.Errorf("Expected no output with zero rate, but got: %s", writer.String())
+	}
+}
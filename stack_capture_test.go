@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStackCapture(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Output:       writer,
+		Color:        ColorModeNever,
+		StackCapture: &StackCapture{},
+	})
+
+	l.Warn("no stack")
+	if strings.Contains(writer.String(), KeyErrorsStack) {
+		t.Errorf("expected no stack below the level: %s", writer.String())
+	}
+
+	writer.Reset()
+	l.Error("with stack")
+	result := writer.String()
+	if !strings.Contains(result, "["+KeyErrorsStack+"]") || !strings.Contains(result, "[github.com/yanun0323/logs.TestStackCapture]") {
+		t.Errorf("expected the stack block starting at the caller: %s", result)
+	}
+
+	for _, internal := range []string{"log/slog.", "(*logger).Error", "logs/internal."} {
+		if strings.Contains(result, internal) {
+			t.Errorf("expected %q to be filtered: %s", internal, result)
+		}
+	}
+}
+
+func TestStackCaptureLevel(t *testing.T) {
+	writer := &bytes.Buffer{}
+	level := LevelInfo
+	l := New(LevelInfo, &Option{
+		Output:       writer,
+		Color:        ColorModeNever,
+		StackCapture: &StackCapture{Level: &level},
+	})
+
+	l.Debug("filtered")
+	l.Info("with stack")
+	if n := strings.Count(writer.String(), "["+KeyErrorsStack+"]"); n != 1 {
+		t.Errorf("expected a stack from the specified level, got %d: %s", n, writer.String())
+	}
+}
+
+func TestStackCaptureJSON(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{
+		Format:       FormatJSON,
+		Output:       writer,
+		StackCapture: &StackCapture{Depth: 2, Skip: []string{"testing."}},
+	})
+
+	l.Error("with stack")
+
+	var record struct {
+		Stack []Frame `json:"error.stack"`
+	}
+	if err := json.Unmarshal(writer.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON %s: %v", writer.String(), err)
+	}
+
+	if len(record.Stack) != 1 || record.Stack[0].Function != "github.com/yanun0323/logs.TestStackCaptureJSON" || record.Stack[0].Line == 0 {
+		t.Errorf("unexpected stack: %s", writer.String())
+	}
+}
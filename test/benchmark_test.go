@@ -2,6 +2,7 @@ package test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/yanun0323/logs"
 )
@@ -22,7 +23,55 @@ func BenchmarkLogsBasic(b *testing.B) {
 		}
 	})
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
+		l.Info("test")
+		l.With("key", "value", "key2", 123.456).Info("test")
+	}
+
+	b.Cleanup(func() {
+		if err := writer.Remove(); err != nil {
+			b.Fatalf("remove writerL failed: %v", err)
+		}
+	})
+}
+
+func BenchmarkLogsSampling(b *testing.B) {
+	writer := switchableWriter(".", "logger.sampling.log")
+
+	l := logs.NewSamplingLogger(logs.LevelInfo, time.Second, 10, 100, &logs.Option{Output: writer})
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			l.Info("test")
+			l.With("key", "value", "key2", 123.456).Info("test")
+		}
+	})
+
+	for i := 0; i < b.N; i++ {
+		l.Info("test")
+		l.With("key", "value", "key2", 123.456).Info("test")
+	}
+
+	b.Cleanup(func() {
+		if err := writer.Remove(); err != nil {
+			b.Fatalf("remove writerL failed: %v", err)
+		}
+	})
+}
+
+func BenchmarkLogsTokenBucket(b *testing.B) {
+	writer := switchableWriter(".", "logger.bucket.log")
+
+	l := logs.NewTokenBucketLogger(logs.LevelInfo, 100, 10, &logs.Option{Output: writer})
+
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			l.Info("test")
+			l.With("key", "value", "key2", 123.456).Info("test")
+		}
+	})
+
+	for i := 0; i < b.N; i++ {
 		l.Info("test")
 		l.With("key", "value", "key2", 123.456).Info("test")
 	}
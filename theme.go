@@ -0,0 +1,295 @@
+package logs
+
+import (
+	"strconv"
+
+	"github.com/yanun0323/logs/internal"
+	"github.com/yanun0323/logs/internal/colorize"
+)
+
+// Color is an ANSI escape sequence used by Theme. An empty Color writes the text without color.
+type Color string
+
+// ColorNone writes the text without color.
+const ColorNone Color = ""
+
+const (
+	ColorBlack   = Color(colorize.ColorBlack)
+	ColorRed     = Color(colorize.ColorRed)
+	ColorGreen   = Color(colorize.ColorGreen)
+	ColorYellow  = Color(colorize.ColorYellow)
+	ColorBlue    = Color(colorize.ColorBlue)
+	ColorMagenta = Color(colorize.ColorMagenta)
+	ColorCyan    = Color(colorize.ColorCyan)
+	ColorWhite   = Color(colorize.ColorWhite)
+
+	ColorBlackReversed   = Color(colorize.ColorBlackReversed)
+	ColorRedReversed     = Color(colorize.ColorRedReversed)
+	ColorGreenReversed   = Color(colorize.ColorGreenReversed)
+	ColorYellowReversed  = Color(colorize.ColorYellowReversed)
+	ColorBlueReversed    = Color(colorize.ColorBlueReversed)
+	ColorMagentaReversed = Color(colorize.ColorMagentaReversed)
+	ColorCyanReversed    = Color(colorize.ColorCyanReversed)
+	ColorWhiteReversed   = Color(colorize.ColorWhiteReversed)
+
+	ColorBrightBlack   = Color(colorize.ColorBrightBlack)
+	ColorBrightRed     = Color(colorize.ColorBrightRed)
+	ColorBrightGreen   = Color(colorize.ColorBrightGreen)
+	ColorBrightYellow  = Color(colorize.ColorBrightYellow)
+	ColorBrightBlue    = Color(colorize.ColorBrightBlue)
+	ColorBrightMagenta = Color(colorize.ColorBrightMagenta)
+	ColorBrightCyan    = Color(colorize.ColorBrightCyan)
+	ColorBrightWhite   = Color(colorize.ColorBrightWhite)
+
+	ColorBrightBlackReversed   = Color(colorize.ColorBrightBlackReversed)
+	ColorBrightRedReversed     = Color(colorize.ColorBrightRedReversed)
+	ColorBrightGreenReversed   = Color(colorize.ColorBrightGreenReversed)
+	ColorBrightYellowReversed  = Color(colorize.ColorBrightYellowReversed)
+	ColorBrightBlueReversed    = Color(colorize.ColorBrightBlueReversed)
+	ColorBrightMagentaReversed = Color(colorize.ColorBrightMagentaReversed)
+	ColorBrightCyanReversed    = Color(colorize.ColorBrightCyanReversed)
+	ColorBrightWhiteReversed   = Color(colorize.ColorBrightWhiteReversed)
+)
+
+// Color256 returns the foreground color n of the 256-color palette.
+func Color256(n uint8) Color {
+	return Color("\x1b[38;5;" + strconv.Itoa(int(n)) + "m")
+}
+
+// ColorRGB returns a 24-bit true color foreground.
+func ColorRGB(r, g, b uint8) Color {
+	return Color("\x1b[38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m")
+}
+
+// Bold returns the color with bold intensity.
+func (c Color) Bold() Color {
+	return "\x1b[1m" + c
+}
+
+// Theme is the color palette of FormatConsole.
+type Theme struct {
+	// Time is the color of the time.
+	Time Color
+
+	// Trace, Debug, Info, Notice, Warn, Error and Fatal are the colors of the level titles.
+	Trace  Color
+	Debug  Color
+	Info   Color
+	Notice Color
+	Warn   Color
+	Error  Color
+	Fatal  Color
+
+	// Message is the color of the message.
+	Message Color
+
+	// Key is the color of the attribute keys without their own color.
+	Key Color
+
+	// Value is the color of the attribute values.
+	Value Color
+
+	// ErrorKey, ContextKey, FuncKey, CauseKey and StackKey are the colors of the highlighted keys
+	// KeyErr, KeyCtx, KeyFunc, "error.cause" and "error.stack".
+	ErrorKey   Color
+	ContextKey Color
+	FuncKey    Color
+	CauseKey   Color
+	StackKey   Color
+
+	// Stack is the color of the functions in the error stack.
+	Stack Color
+
+	// Keys highlights the attribute keys with their own color, it overrides the colors above.
+	Keys map[string]Color
+}
+
+// WithKey returns a copy of the theme which highlights the attribute key with the color.
+func (t Theme) WithKey(key string, color Color) Theme {
+	keys := make(map[string]Color, len(t.Keys)+1)
+	for k, c := range t.Keys {
+		keys[k] = c
+	}
+	keys[key] = color
+	t.Keys = keys
+
+	return t
+}
+
+// newTheme converts the palette of the console handler, which is the reverse of Theme.internal.
+func newTheme(t *internal.Theme) Theme {
+	theme := Theme{
+		Time:       Color(t.Time),
+		Trace:      Color(t.Levels[int8(LevelTrace)]),
+		Debug:      Color(t.Levels[int8(LevelDebug)]),
+		Info:       Color(t.Levels[int8(LevelInfo)]),
+		Notice:     Color(t.Levels[int8(LevelNotice)]),
+		Warn:       Color(t.Levels[int8(LevelWarn)]),
+		Error:      Color(t.Levels[int8(LevelError)]),
+		Fatal:      Color(t.Levels[int8(LevelFatal)]),
+		Message:    Color(t.Message),
+		Key:        Color(t.Key),
+		Value:      Color(t.Value),
+		ErrorKey:   Color(t.Keys[KeyErr]),
+		ContextKey: Color(t.Keys[KeyCtx]),
+		FuncKey:    Color(t.Keys[KeyFunc]),
+		CauseKey:   Color(t.Keys[internal.KeyErrorsCause]),
+		StackKey:   Color(t.Keys[internal.KeyErrorsStack]),
+		Stack:      Color(t.Stack),
+	}
+
+	for k, c := range t.Keys {
+		switch k {
+		case KeyErr, KeyCtx, KeyFunc, internal.KeyErrorsCause, internal.KeyErrorsStack:
+		default:
+			if theme.Keys == nil {
+				theme.Keys = make(map[string]Color)
+			}
+			theme.Keys[k] = Color(c)
+		}
+	}
+
+	return theme
+}
+
+func (t *Theme) internal() *internal.Theme {
+	keys := map[string]colorize.Color{
+		KeyErr:                  colorize.Color(t.ErrorKey),
+		KeyCtx:                  colorize.Color(t.ContextKey),
+		KeyFunc:                 colorize.Color(t.FuncKey),
+		internal.KeyErrorsCause: colorize.Color(t.CauseKey),
+		internal.KeyErrorsStack: colorize.Color(t.StackKey),
+	}
+
+	for k, c := range t.Keys {
+		keys[k] = colorize.Color(c)
+	}
+
+	return &internal.Theme{
+		Time:    colorize.Color(t.Time),
+		Message: colorize.Color(t.Message),
+		Key:     colorize.Color(t.Key),
+		Value:   colorize.Color(t.Value),
+		Stack:   colorize.Color(t.Stack),
+		Levels: map[int8]colorize.Color{
+			int8(LevelTrace):  colorize.Color(t.Trace),
+			int8(LevelDebug):  colorize.Color(t.Debug),
+			int8(LevelInfo):   colorize.Color(t.Info),
+			int8(LevelNotice): colorize.Color(t.Notice),
+			int8(LevelWarn):   colorize.Color(t.Warn),
+			int8(LevelError):  colorize.Color(t.Error),
+			int8(LevelFatal):  colorize.Color(t.Fatal),
+		},
+		Keys: keys,
+	}
+}
+
+var (
+	// ThemeDefault is the default palette of FormatConsole.
+	ThemeDefault = newTheme(internal.DefaultTheme)
+
+	// ThemeDark is a palette for terminals with dark background.
+	ThemeDark = Theme{
+		Time:       ColorBrightBlack,
+		Trace:      ColorBrightBlack,
+		Debug:      ColorBrightBlue,
+		Info:       ColorBrightGreen,
+		Notice:     ColorBrightCyan,
+		Warn:       ColorBrightYellow,
+		Error:      ColorBrightRed,
+		Fatal:      ColorBrightRedReversed,
+		Message:    ColorBrightWhite,
+		Key:        ColorBrightMagenta,
+		Value:      ColorWhite,
+		ErrorKey:   ColorBrightRed,
+		ContextKey: ColorBrightCyan,
+		FuncKey:    ColorBrightBlue,
+		CauseKey:   ColorBrightYellow,
+		StackKey:   ColorBrightCyan,
+		Stack:      ColorBrightBlue,
+	}
+
+	// ThemeLight is a palette for terminals with light background.
+	ThemeLight = Theme{
+		Time:       ColorBrightBlack,
+		Trace:      ColorBrightBlack,
+		Debug:      ColorBlue,
+		Info:       ColorGreen,
+		Notice:     ColorCyan,
+		Warn:       ColorMagenta,
+		Error:      ColorRed,
+		Fatal:      ColorRedReversed,
+		Message:    ColorBlack,
+		Key:        ColorMagenta,
+		Value:      ColorBlack,
+		ErrorKey:   ColorRed,
+		ContextKey: ColorCyan,
+		FuncKey:    ColorBlue,
+		CauseKey:   ColorMagenta,
+		StackKey:   ColorCyan,
+		Stack:      ColorBlue,
+	}
+
+	// ThemeHighContrast is a palette with bold colors and reversed levels for better accessibility.
+	ThemeHighContrast = Theme{
+		Time:       ColorBrightWhite,
+		Trace:      ColorBrightWhiteReversed,
+		Debug:      ColorBrightBlueReversed,
+		Info:       ColorBrightGreenReversed,
+		Notice:     ColorBrightCyanReversed,
+		Warn:       ColorBrightYellowReversed,
+		Error:      ColorBrightRedReversed,
+		Fatal:      ColorRedReversed.Bold(),
+		Message:    ColorBrightWhite.Bold(),
+		Key:        ColorBrightMagenta.Bold(),
+		Value:      ColorBrightWhite,
+		ErrorKey:   ColorBrightRed.Bold(),
+		ContextKey: ColorBrightCyan.Bold(),
+		FuncKey:    ColorBrightBlue.Bold(),
+		CauseKey:   ColorBrightYellow.Bold(),
+		StackKey:   ColorBrightCyan.Bold(),
+		Stack:      ColorBrightBlue,
+	}
+
+	// Theme256 is a palette using the 256-color palette.
+	Theme256 = Theme{
+		Time:       Color256(245),
+		Trace:      Color256(244),
+		Debug:      Color256(75),
+		Info:       Color256(114),
+		Notice:     Color256(80),
+		Warn:       Color256(221),
+		Error:      Color256(203),
+		Fatal:      Color256(196).Bold(),
+		Message:    Color256(255),
+		Key:        Color256(176),
+		Value:      Color256(250),
+		ErrorKey:   Color256(203),
+		ContextKey: Color256(80),
+		FuncKey:    Color256(111),
+		CauseKey:   Color256(221),
+		StackKey:   Color256(80),
+		Stack:      Color256(111),
+	}
+
+	// ThemeTrueColor is a palette using 24-bit true colors.
+	ThemeTrueColor = Theme{
+		Time:       ColorRGB(128, 128, 128),
+		Trace:      ColorRGB(120, 120, 120),
+		Debug:      ColorRGB(97, 175, 239),
+		Info:       ColorRGB(152, 195, 121),
+		Notice:     ColorRGB(86, 182, 194),
+		Warn:       ColorRGB(229, 192, 123),
+		Error:      ColorRGB(224, 108, 117),
+		Fatal:      ColorRGB(255, 85, 85).Bold(),
+		Message:    ColorRGB(220, 223, 228),
+		Key:        ColorRGB(198, 120, 221),
+		Value:      ColorRGB(171, 178, 191),
+		ErrorKey:   ColorRGB(224, 108, 117),
+		ContextKey: ColorRGB(86, 182, 194),
+		FuncKey:    ColorRGB(97, 175, 239),
+		CauseKey:   ColorRGB(229, 192, 123),
+		StackKey:   ColorRGB(86, 182, 194),
+		Stack:      ColorRGB(97, 175, 239),
+	}
+)
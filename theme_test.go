@@ -0,0 +1,43 @@
+package logs
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/logs/internal"
+)
+
+func TestTheme(t *testing.T) {
+	highlight := ColorRGB(255, 0, 128)
+	theme := ThemeDark.WithKey("user_id", highlight)
+
+	if len(ThemeDark.Keys) != 0 {
+		t.Fatalf("WithKey should not modify the original theme")
+	}
+
+	writer := &bytes.Buffer{}
+	New(LevelInfo, &Option{Output: writer, Color: ColorModeAlways, Theme: &theme}).
+		With("user_id", "i'm user").
+		Warn("message")
+
+	result := writer.String()
+	if !strings.Contains(result, string(highlight)+"[user_id]") {
+		t.Errorf("expected highlighted key in output: %q", result)
+	}
+
+	if !strings.Contains(result, string(ThemeDark.Warn)+"WARN") {
+		t.Errorf("expected themed level in output: %q", result)
+	}
+
+	if !strings.Contains(result, string(ThemeDark.Message)+"message") {
+		t.Errorf("expected themed message in output: %q", result)
+	}
+}
+
+func TestThemeDefault(t *testing.T) {
+	if !reflect.DeepEqual(ThemeDefault.internal(), internal.DefaultTheme) {
+		t.Errorf("expected ThemeDefault to be the palette of the console handler:\n%+v\n%+v", ThemeDefault.internal(), internal.DefaultTheme)
+	}
+}
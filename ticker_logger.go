@@ -6,11 +6,30 @@ import (
 	"time"
 )
 
+// TickerLogger is implemented by the loggers which drop messages to limit the output rate,
+// e.g. the loggers created by NewTickerLogger:
+//
+//	stats := l.(logs.TickerLogger).Stats()
+type TickerLogger interface {
+	Logger
+
+	// Stats returns the counters of the logger, including the loggers derived from it.
+	Stats() TickerStats
+}
+
+// TickerStats is a snapshot of the counters of a ticker logger.
+type TickerStats struct {
+	// Emitted is the number of messages passed through the ticker.
+	Emitted uint64
+
+	// Suppressed is the number of messages dropped by the ticker.
+	Suppressed uint64
+}
+
 type tickerLogger struct {
-	last                int64
-	sender              atomic.Bool
-	intervalMillisecond int64
-	nextFireTime        int64
+	gate   *tickerGate
+	gates  map[Level]*tickerGate
+	bypass Level
 
 	Logger
 }
@@ -20,54 +39,79 @@ type tickerLogger struct {
 // A ticker logger is a logger that logs messages only when the interval has passed,
 // otherwise the messages will be dropped.
 //
+// When the interval passes, the logged message carries the field KeySuppressed with the number of
+// messages dropped since the previous one, and the fields KeySuppressedFirst and KeySuppressedLast
+// with the time of the first and the last dropped message.
+//
 // If option is not provided, the logger will write to the os.Stdout with console format.
 func NewTickerLogger(level Level, interval time.Duration, option ...*Option) Logger {
-	itv := interval.Milliseconds()
-	now := time.Now().UnixMilli()
-	return &tickerLogger{
-		last:                now - itv,
-		intervalMillisecond: itv,
-		nextFireTime:        now,
-		Logger:              New(level, option...),
-	}
+	return NewTickerLoggerWithPolicy(level, NewTickerPolicy(interval), option...)
 }
 
-func (l *tickerLogger) canBeFire() bool {
-	now := time.Now().UnixMilli()
-	nextFire := atomic.LoadInt64(&l.nextFireTime)
+// NewTickerLoggerWithPolicy creates a new ticker logger with the given policy and level.
+//
+// It works like NewTickerLogger, except that the levels are throttled as specified by the policy.
+func NewTickerLoggerWithPolicy(level Level, policy *TickerPolicy, option ...*Option) Logger {
+	if policy == nil {
+		policy = NewTickerPolicy(0)
+	}
 
-	if now < nextFire {
-		return false
+	counter := &tickerCounter{}
+	l := &tickerLogger{
+		gate:   newTickerGate(policy.interval, counter),
+		bypass: policy.bypass,
+		Logger: New(level, option...),
 	}
 
-	if !l.sender.CompareAndSwap(false, true) {
-		return false
+	if len(policy.intervals) != 0 {
+		l.gates = make(map[Level]*tickerGate, len(policy.intervals))
+		for lvl, itv := range policy.intervals {
+			l.gates[lvl] = newTickerGate(itv, counter)
+		}
 	}
 
-	now = time.Now().UnixMilli()
-	nextFire = atomic.LoadInt64(&l.nextFireTime)
+	return l
+}
 
-	if now < nextFire {
-		l.sender.Store(false)
-		return false
+// fire returns the logger to log the message with, and false when the message should be dropped.
+func (l *tickerLogger) fire(level Level) (Logger, bool) {
+	if level >= l.bypass {
+		l.gate.counter.emitted.Add(1)
+		return l.Logger, true
+	}
+
+	if gate, ok := l.gates[level]; ok {
+		return gate.fire(l.Logger)
 	}
 
-	newNextFire := now + l.intervalMillisecond
-	atomic.StoreInt64(&l.last, now)
-	atomic.StoreInt64(&l.nextFireTime, newNextFire)
+	return l.gate.fire(l.Logger)
+}
+
+// clone copies the gates of the logger with the given inner logger, the counter is shared.
+func (l *tickerLogger) clone(logger Logger) *tickerLogger {
+	counter := l.gate.counter
+	ll := &tickerLogger{
+		gate:   l.gate.copy(counter),
+		bypass: l.bypass,
+		Logger: logger,
+	}
 
-	l.sender.Store(false)
+	if len(l.gates) != 0 {
+		ll.gates = make(map[Level]*tickerGate, len(l.gates))
+		for lvl, gate := range l.gates {
+			ll.gates[lvl] = gate.copy(counter)
+		}
+	}
 
-	return true
+	return ll
+}
+
+func (l *tickerLogger) Stats() TickerStats {
+	return l.gate.counter.stats()
 }
 
 func (l *tickerLogger) Copy() Logger {
-	return &tickerLogger{
-		last:                atomic.LoadInt64(&l.last),
-		intervalMillisecond: l.intervalMillisecond,
-		nextFireTime:        atomic.LoadInt64(&l.nextFireTime),
-		Logger:              l.Logger.Copy(),
-	}
+	return l.clone(l.Logger.Copy())
 }
 
 func (l *tickerLogger) WithError(err error) Logger {
@@ -75,90 +119,290 @@ func (l *tickerLogger) WithError(err error) Logger {
 }
 
 func (l *tickerLogger) WithFunc(function string) Logger {
-	return l.With(KeyFunc, function)
+	return l.clone(l.Logger.WithFunc(function))
+}
+
+func (l *tickerLogger) AppendFunc(function string) Logger {
+	return l.clone(l.Logger.AppendFunc(function))
+}
+
+func (l *tickerLogger) AppendCaller() Logger {
+	return l.AppendFunc(callerFunc(1))
 }
 
 func (l *tickerLogger) WithCtx(ctx context.Context) Logger {
-	return l.With(KeyCtx, ctx)
+	return l.clone(l.Logger.WithCtx(ctx))
 }
 
 func (l *tickerLogger) With(args ...any) Logger {
-	return &tickerLogger{
-		last:                atomic.LoadInt64(&l.last),
-		intervalMillisecond: l.intervalMillisecond,
-		nextFireTime:        atomic.LoadInt64(&l.nextFireTime),
-		Logger:              l.Logger.With(args...),
-	}
+	return l.clone(l.Logger.With(args...))
 }
 
 func (l *tickerLogger) Attach(ctx context.Context) context.Context {
 	return context.WithValue(ctx, logAttachKey, l)
 }
 
+func (l *tickerLogger) bindContext(ctx context.Context) Logger {
+	// the gates are shared, so the messages are throttled together with the logger in the context
+	ll := *l
+	ll.Logger = bindContext(l.Logger, ctx)
+	return &ll
+}
+
 func (l *tickerLogger) Log(level Level, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Log(level, args...)
+	if logger, ok := l.fire(level); ok {
+		logger.Log(level, args...)
 	}
 }
 
 func (l *tickerLogger) Logf(level Level, format string, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Logf(level, format, args...)
+	if logger, ok := l.fire(level); ok {
+		logger.Logf(level, format, args...)
+	}
+}
+
+func (l *tickerLogger) Trace(args ...any) {
+	if logger, ok := l.fire(LevelTrace); ok {
+		logger.Trace(args...)
+	}
+}
+
+func (l *tickerLogger) Tracef(format string, args ...any) {
+	if logger, ok := l.fire(LevelTrace); ok {
+		logger.Tracef(format, args...)
 	}
 }
 
 func (l *tickerLogger) Debug(args ...any) {
-	if l.canBeFire() {
-		l.Logger.Debug(args...)
+	if logger, ok := l.fire(LevelDebug); ok {
+		logger.Debug(args...)
 	}
 }
 
 func (l *tickerLogger) Debugf(format string, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Debugf(format, args...)
+	if logger, ok := l.fire(LevelDebug); ok {
+		logger.Debugf(format, args...)
 	}
 }
 
 func (l *tickerLogger) Info(args ...any) {
-	if l.canBeFire() {
-		l.Logger.Info(args...)
+	if logger, ok := l.fire(LevelInfo); ok {
+		logger.Info(args...)
 	}
 }
 
 func (l *tickerLogger) Infof(format string, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Infof(format, args...)
+	if logger, ok := l.fire(LevelInfo); ok {
+		logger.Infof(format, args...)
+	}
+}
+
+func (l *tickerLogger) Notice(args ...any) {
+	if logger, ok := l.fire(LevelNotice); ok {
+		logger.Notice(args...)
+	}
+}
+
+func (l *tickerLogger) Noticef(format string, args ...any) {
+	if logger, ok := l.fire(LevelNotice); ok {
+		logger.Noticef(format, args...)
 	}
 }
 
 func (l *tickerLogger) Warn(args ...any) {
-	if l.canBeFire() {
-		l.Logger.Warn(args...)
+	if logger, ok := l.fire(LevelWarn); ok {
+		logger.Warn(args...)
 	}
 }
 
 func (l *tickerLogger) Warnf(format string, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Warnf(format, args...)
+	if logger, ok := l.fire(LevelWarn); ok {
+		logger.Warnf(format, args...)
 	}
 }
 
 func (l *tickerLogger) Error(args ...any) {
-	if l.canBeFire() {
-		l.Logger.Error(args...)
+	if logger, ok := l.fire(LevelError); ok {
+		logger.Error(args...)
 	}
 }
 
 func (l *tickerLogger) Errorf(format string, args ...any) {
-	if l.canBeFire() {
-		l.Logger.Errorf(format, args...)
+	if logger, ok := l.fire(LevelError); ok {
+		logger.Errorf(format, args...)
 	}
 }
 
 func (l *tickerLogger) Fatal(args ...any) {
-	l.Logger.Fatal(args...)
+	if logger, ok := l.fire(LevelFatal); ok {
+		logger.Fatal(args...)
+	}
 }
 
 func (l *tickerLogger) Fatalf(format string, args ...any) {
-	l.Logger.Fatalf(format, args...)
+	if logger, ok := l.fire(LevelFatal); ok {
+		logger.Fatalf(format, args...)
+	}
+}
+
+func (l *tickerLogger) RecoverPanic() {
+	if p := recover(); p != nil {
+		logPanic(l.Logger, p)
+	}
+}
+
+func (l *tickerLogger) TraceFunc() func() {
+	return traceFunc(l.AppendFunc(callerFunc(1)))
+}
+
+func (l *tickerLogger) Start(name string) *Timer {
+	return newTimer(l, name)
+}
+
+// tickerGate opens once per interval.
+type tickerGate struct {
+	last                int64
+	sender              atomic.Bool
+	intervalMillisecond int64
+	nextFireTime        int64
+
+	// suppressed is the number of the dropped messages since the gate opened,
+	// firstSuppressed and lastSuppressed are the unix milliseconds of the first and the last one.
+	suppressed      int64
+	firstSuppressed int64
+	lastSuppressed  int64
+
+	counter *tickerCounter
+}
+
+func newTickerGate(interval time.Duration, counter *tickerCounter) *tickerGate {
+	itv := interval.Milliseconds()
+	now := time.Now().UnixMilli()
+	return &tickerGate{
+		last:                now - itv,
+		intervalMillisecond: itv,
+		nextFireTime:        now,
+		counter:             counter,
+	}
+}
+
+// fire returns the logger to log the message with, and false when the message should be dropped.
+//
+// The returned logger carries the suppressed fields when there are dropped messages.
+func (g *tickerGate) fire(logger Logger) (Logger, bool) {
+	suppressed, first, last, ok := g.canBeFire()
+	if !ok {
+		return nil, false
+	}
+
+	if suppressed == 0 {
+		return logger, true
+	}
+
+	return logger.With(
+		KeySuppressed, suppressed,
+		KeySuppressedFirst, time.UnixMilli(first),
+		KeySuppressedLast, time.UnixMilli(last),
+	), true
+}
+
+// canBeFire reports whether the gate opens, and the dropped messages since the gate opened last time.
+func (g *tickerGate) canBeFire() (suppressed, first, last int64, ok bool) {
+	now := time.Now().UnixMilli()
+	nextFire := atomic.LoadInt64(&g.nextFireTime)
+
+	if now < nextFire {
+		g.suppress(now)
+		return 0, 0, 0, false
+	}
+
+	if !g.sender.CompareAndSwap(false, true) {
+		g.suppress(now)
+		return 0, 0, 0, false
+	}
+
+	now = time.Now().UnixMilli()
+	nextFire = atomic.LoadInt64(&g.nextFireTime)
+
+	if now < nextFire {
+		g.sender.Store(false)
+		g.suppress(now)
+		return 0, 0, 0, false
+	}
+
+	newNextFire := now + g.intervalMillisecond
+	atomic.StoreInt64(&g.last, now)
+	atomic.StoreInt64(&g.nextFireTime, newNextFire)
+
+	suppressed = atomic.SwapInt64(&g.suppressed, 0)
+	first = atomic.LoadInt64(&g.firstSuppressed)
+	last = atomic.LoadInt64(&g.lastSuppressed)
+
+	g.sender.Store(false)
+	g.counter.emitted.Add(1)
+
+	return suppressed, first, last, true
+}
+
+func (g *tickerGate) suppress(now int64) {
+	if atomic.AddInt64(&g.suppressed, 1) == 1 {
+		atomic.StoreInt64(&g.firstSuppressed, now)
+	}
+	atomic.StoreInt64(&g.lastSuppressed, now)
+	g.counter.suppressed.Add(1)
+}
+
+// copy duplicates the schedule of the gate with the given counter, the dropped messages are not copied.
+func (g *tickerGate) copy(counter *tickerCounter) *tickerGate {
+	return &tickerGate{
+		last:                atomic.LoadInt64(&g.last),
+		intervalMillisecond: g.intervalMillisecond,
+		nextFireTime:        atomic.LoadInt64(&g.nextFireTime),
+		counter:             counter,
+	}
+}
+
+// tickerCounter counts the messages passed through or dropped by the gates.
+type tickerCounter struct {
+	emitted    atomic.Uint64
+	suppressed atomic.Uint64
+}
+
+func (c *tickerCounter) stats() TickerStats {
+	return TickerStats{
+		Emitted:    c.emitted.Load(),
+		Suppressed: c.suppressed.Load(),
+	}
+}
+
+// TickerPolicy specifies how a ticker logger throttles each level.
+type TickerPolicy struct {
+	interval  time.Duration
+	intervals map[Level]time.Duration
+	bypass    Level
+}
+
+// NewTickerPolicy creates a policy which throttles every level below LevelFatal with the interval.
+func NewTickerPolicy(interval time.Duration) *TickerPolicy {
+	return &TickerPolicy{
+		interval: interval,
+		bypass:   LevelFatal,
+	}
+}
+
+// Bypass makes the levels at or above the given level never be throttled.
+//
+// LevelFatal is always bypassed.
+func (p *TickerPolicy) Bypass(level Level) *TickerPolicy {
+	p.bypass = min(level, LevelFatal)
+	return p
+}
+
+// Interval sets a separate interval for the level, the level is throttled independently of the others.
+func (p *TickerPolicy) Interval(level Level, interval time.Duration) *TickerPolicy {
+	if p.intervals == nil {
+		p.intervals = make(map[Level]time.Duration)
+	}
+	p.intervals[level] = interval
+	return p
 }
@@ -53,3 +53,60 @@ func TestTickerLoggerInterval(t *testing.T) {
 		t.Errorf("Expected at most 3 log lines, got %d", lines)
 	}
 }
+
+func TestTickerLoggerSuppressed(t *testing.T) {
+	writer := &bytes.Buffer{}
+	timer := NewTickerLogger(LevelDebug, 50*time.Millisecond, &Option{Output: writer})
+
+	timer.Info("first")
+	for i := 0; i < 5; i++ {
+		timer.Info("dropped")
+	}
+
+	if strings.Contains(writer.String(), KeySuppressed) {
+		t.Errorf("Expected no suppressed field in the first message: %s", writer.String())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	writer.Reset()
+	timer.Info("second")
+
+	if !strings.Contains(writer.String(), "[suppressed] 5") {
+		t.Errorf("Expected suppressed field in the message: %s", writer.String())
+	}
+
+	if !strings.Contains(writer.String(), KeySuppressedFirst) || !strings.Contains(writer.String(), KeySuppressedLast) {
+		t.Errorf("Expected suppressed time fields in the message: %s", writer.String())
+	}
+
+	if stats := timer.(TickerLogger).Stats(); stats.Emitted != 2 || stats.Suppressed != 5 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	timer.With("child", true).Info("child")
+	if stats := timer.(TickerLogger).Stats(); stats.Emitted+stats.Suppressed != 8 {
+		t.Errorf("Expected the stats to count the derived loggers: %+v", stats)
+	}
+}
+
+func TestTickerLoggerPolicy(t *testing.T) {
+	writer := &bytes.Buffer{}
+	policy := NewTickerPolicy(time.Hour).
+		Bypass(LevelError).
+		Interval(LevelDebug, time.Hour)
+	timer := NewTickerLoggerWithPolicy(LevelDebug, policy, &Option{Output: writer})
+
+	for i := 0; i < 3; i++ {
+		timer.Debug("debug")
+		timer.Info("info")
+		timer.Warn("warn")
+		timer.Error("error")
+	}
+
+	result := writer.String()
+	for msg, want := range map[string]int{"debug": 1, "info": 1, "warn": 0, "error": 3} {
+		if got := strings.Count(result, " "+msg+" "); got != want {
+			t.Errorf("Expected %d %s lines, but got %d: %s", want, msg, got, result)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package logs
+
+import "time"
+
+// Timer measures the elapsed time of an operation, see Logger.Start.
+type Timer struct {
+	logger    Logger
+	name      string
+	start     time.Time
+	threshold time.Duration
+}
+
+func newTimer(logger Logger, name string) *Timer {
+	return &Timer{
+		logger: logger,
+		name:   name,
+		start:  time.Now(),
+	}
+}
+
+// Threshold makes Stop log at the warn level when the elapsed time exceeds d.
+// Zero threshold never escalates.
+func (t *Timer) Threshold(d time.Duration) *Timer {
+	t.threshold = d
+	return t
+}
+
+// Elapsed returns the time elapsed since the timer started.
+func (t *Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Stop logs the name of the timer with the elapsed time (using the key defined in KeyElapsed)
+// and the pairs of key and value, and returns the elapsed time.
+//
+// It logs at the info level, or at the warn level when the elapsed time exceeds the threshold.
+func (t *Timer) Stop(args ...any) time.Duration {
+	elapsed := t.Elapsed()
+
+	level := LevelInfo
+	if t.threshold > 0 && elapsed > t.threshold {
+		level = LevelWarn
+	}
+
+	t.logger.With(KeyElapsed, elapsed).With(args...).Log(level, t.name)
+
+	return elapsed
+}
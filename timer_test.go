@@ -0,0 +1,59 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelInfo, &Option{Output: writer, Format: FormatJSON})
+
+	elapsed := l.Start("query").Stop("rows", 3)
+	result := writer.String()
+
+	for _, s := range []string{`"level":"INFO"`, `"msg":"query"`, `"rows":3`, `"` + KeyElapsed + `":`} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected %q in output: %s", s, result)
+		}
+	}
+
+	if elapsed <= 0 {
+		t.Errorf("expected a positive elapsed time, but got %s", elapsed)
+	}
+
+	writer.Reset()
+	timer := l.Start("slow query").Threshold(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	timer.Stop()
+
+	if result := writer.String(); !strings.Contains(result, `"level":"WARN"`) {
+		t.Errorf("expected the warn level when the threshold is exceeded: %s", result)
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	writer := &bytes.Buffer{}
+	New(LevelInfo, &Option{Output: writer, Color: ColorModeNever}).With(KeyElapsed, 1500*time.Microsecond).Info("console")
+
+	if result := writer.String(); !strings.Contains(result, "1.5ms") {
+		t.Errorf("expected a human readable duration: %s", result)
+	}
+
+	writer.Reset()
+	New(LevelInfo, &Option{Output: writer, Format: FormatJSON}).With(KeyElapsed, 1500*time.Microsecond).Info("json")
+
+	if result := writer.String(); !strings.Contains(result, `"elapsed":1500000`) {
+		t.Errorf("expected the duration in nanoseconds: %s", result)
+	}
+
+	writer.Reset()
+	New(LevelInfo, &Option{Output: writer, Format: FormatJSON, DurationUnit: time.Millisecond}).
+		With(KeyElapsed, 1500*time.Microsecond).Info("json")
+
+	if result := writer.String(); !strings.Contains(result, `"elapsed":1.5`) {
+		t.Errorf("expected the duration in milliseconds: %s", result)
+	}
+}
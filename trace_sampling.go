@@ -0,0 +1,85 @@
+package logs
+
+import (
+	"context"
+	"math"
+)
+
+type traceIDKey struct{}
+
+// ContextWithTraceID returns a copy of ctx carrying the trace ID, which is used by TraceSampling.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID set by ContextWithTraceID, or an empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// TraceSampling keeps or drops the messages of a whole trace, decided by the hash of its trace ID,
+// so that every message of a sampled trace is written and none of an unsampled one.
+//
+// The context of a message is the one given to Logger.WithCtx, or the one passed to Get.
+// Messages without a trace ID are always written.
+type TraceSampling struct {
+	// Rate is the fraction of traces to keep, from 0 to 1.
+	Rate float64
+
+	// Level is the highest level to sample, messages above it are always written.
+	// Defaults to LevelInfo.
+	Level Level
+
+	// TraceID returns the trace ID of the context.
+	// Defaults to TraceIDFromContext if not specified.
+	TraceID func(ctx context.Context) string
+}
+
+// keep returns a function reporting whether the messages of the context should be written.
+func (s *TraceSampling) keep() func(ctx context.Context) bool {
+	traceID := s.TraceID
+	if traceID == nil {
+		traceID = TraceIDFromContext
+	}
+
+	var threshold uint64
+	switch {
+	case s.Rate >= 1:
+		threshold = math.MaxUint64
+	case s.Rate > 0:
+		threshold = uint64(s.Rate * math.MaxUint64)
+	}
+
+	return func(ctx context.Context) bool {
+		id := traceID(ctx)
+		if len(id) == 0 || threshold == math.MaxUint64 {
+			return true
+		}
+
+		return traceHash(id) < threshold
+	}
+}
+
+// traceHash hashes the trace ID with FNV-1a, so the decision is the same across processes.
+// The hash is mixed with the finalizer of SplitMix64, since FNV-1a barely changes the high bits
+// of similar IDs.
+func traceHash(id string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(id); i++ {
+		h = (h ^ uint64(id[i])) * prime64
+	}
+
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
@@ -0,0 +1,108 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTraceSampling(t *testing.T) {
+	writer := &bytes.Buffer{}
+	l := New(LevelDebug, &Option{
+		Format:   FormatJSON,
+		Output:   writer,
+		Sampling: &TraceSampling{Rate: 0.5},
+	})
+
+	kept := 0
+	for i := 0; i < 200; i++ {
+		ctx := ContextWithTraceID(context.Background(), fmt.Sprintf("trace-%d", i))
+		logger := Get(l.Attach(ctx))
+
+		writer.Reset()
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warn("warn")
+
+		lines := strings.Count(writer.String(), "\n")
+		switch lines {
+		case 3:
+			kept++
+		case 1:
+			if !strings.Contains(writer.String(), "warn") {
+				t.Fatalf("expected warn to be kept, got: %s", writer.String())
+			}
+		default:
+			t.Fatalf("expected all or none of the sampled messages of a trace, got: %s", writer.String())
+		}
+	}
+
+	if kept < 60 || kept > 140 {
+		t.Errorf("expected about half of the traces to be kept, got %d", kept)
+	}
+
+	writer.Reset()
+	l.Debug("no trace")
+	if !strings.Contains(writer.String(), "no trace") {
+		t.Errorf("expected message without trace ID to be kept, got: %s", writer.String())
+	}
+}
+
+type requestIDKey struct{}
+
+func TestTraceSamplingConsistent(t *testing.T) {
+	writer := &bytes.Buffer{}
+	newLogger := func(rate float64) Logger {
+		return New(LevelDebug, &Option{
+			Format: FormatJSON,
+			Output: writer,
+			Sampling: &TraceSampling{
+				Rate:    rate,
+				TraceID: func(ctx context.Context) string { s, _ := ctx.Value(requestIDKey{}).(string); return s },
+			},
+		})
+	}
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+
+	writer.Reset()
+	newLogger(0).WithCtx(ctx).Info("dropped")
+	if writer.Len() != 0 {
+		t.Errorf("expected message to be dropped with zero rate, got: %s", writer.String())
+	}
+
+	newLogger(1).WithCtx(ctx).Info("kept")
+	if !strings.Contains(writer.String(), "kept") {
+		t.Errorf("expected message to be kept with full rate, got: %s", writer.String())
+	}
+
+	first := newLogger(0.3).WithCtx(ctx)
+	writer.Reset()
+	first.Info("a")
+	expected := writer.Len() != 0
+	for i := 0; i < 10; i++ {
+		writer.Reset()
+		newLogger(0.3).WithCtx(ctx).With("i", i).Info("a")
+		if (writer.Len() != 0) != expected {
+			t.Fatalf("expected the same decision for the same trace ID")
+		}
+	}
+}
+
+func TestTraceSamplingWrappers(t *testing.T) {
+	for name, newLogger := range testLoggers(LevelDebug) {
+		writer := &bytes.Buffer{}
+		l := newLogger(&Option{Output: writer, Format: FormatJSON, Sampling: &TraceSampling{Rate: 0}})
+
+		ctx := ContextWithTraceID(context.Background(), "trace")
+		logger := Get(l.Attach(ctx))
+		logger.Debug("dropped")
+		logger.Error("kept")
+
+		if result := writer.String(); strings.Contains(result, "dropped") || !strings.Contains(result, "kept") {
+			t.Errorf("%s: expected the trace to be sampled out: %s", name, result)
+		}
+	}
+}